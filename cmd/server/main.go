@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	_ "github.com/ariyaagustian/gin-boilerplate/docs" // docs is generated by Swag CLI, you have to import it.
 
@@ -11,10 +13,16 @@ import (
 	"github.com/ariyaagustian/gin-boilerplate/internal/config"
 	"github.com/ariyaagustian/gin-boilerplate/internal/db"
 	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/middleware"
 	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
 	"github.com/ariyaagustian/gin-boilerplate/internal/service"
 	transport "github.com/ariyaagustian/gin-boilerplate/internal/transport/http"
 	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/handler"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/cursor"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/mailer"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/oidc"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/password"
 )
 
 // @title Gin CRUD Boilerplate API
@@ -36,29 +44,284 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Personal API key (gbk_...), dibuat lewat /api/v1/me/api-keys.
+
 func main() {
 	// load config & db
 	cfg := config.Load()
 	var gdb *gorm.DB = db.Open(cfg.DSN)
-	if err := gdb.AutoMigrate(&domain.User{}); err != nil {
+	// dicek sebelum AutoMigrate: kolom baru = user lama perlu di-backfill (lihat di bawah)
+	backfillEmailVerified := !gdb.Migrator().HasColumn(&domain.User{}, "email_verified_at")
+	if err := gdb.AutoMigrate(
+		&domain.User{},
+		&domain.RefreshToken{},
+		&domain.RevokedToken{},
+		&domain.UserTokenRevocation{},
+		&domain.Role{},
+		&domain.RolePermission{},
+		&domain.UserRole{},
+		&domain.ActionToken{},
+		&domain.UserMFA{},
+		&domain.MFARecoveryCode{},
+		&domain.UserIdentity{},
+		&domain.OAuthState{},
+		&domain.LoginAttempt{},
+		&domain.APIKey{},
+		&domain.Session{},
+		&domain.AuditLog{},
+		&domain.Invitation{},
+	); err != nil {
 		log.Fatal("auto migrate:", err)
 	}
+	// user yang sudah ada sebelum verifikasi email dianggap terverifikasi, supaya
+	// EMAIL_VERIFICATION_POLICY=login/restrict tidak mengunci semua akun lama
+	if backfillEmailVerified {
+		if err := gdb.Model(&domain.User{}).
+			Where("email_verified_at IS NULL").
+			Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			log.Fatal("backfill email_verified_at:", err)
+		}
+	}
+	// index unik email lama (sebelum soft delete) diganti idx_users_email_active yang mengabaikan user terhapus
+	if gdb.Migrator().HasIndex(&domain.User{}, "idx_users_email") {
+		if err := gdb.Migrator().DropIndex(&domain.User{}, "idx_users_email"); err != nil {
+			log.Fatal("drop index idx_users_email:", err)
+		}
+	}
+	db.RunMigrations(gdb)
 
 	// wiring dependency
 	v := validator.New()
 	userRepo := repository.NewUserRepository(gdb)
+	refreshRepo := repository.NewRefreshTokenRepository(gdb)
+	roleRepo := repository.NewRoleRepository(gdb)
+	actionTokenRepo := repository.NewActionTokenRepository(gdb)
+	mfaRepo := repository.NewMFARepository(gdb)
+	identityRepo := repository.NewIdentityRepository(gdb)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(gdb)
+	apiKeyRepo := repository.NewAPIKeyRepository(gdb)
+	sessionRepo := repository.NewSessionRepository(gdb)
+	auditRepo := repository.NewAuditLogRepository(gdb)
+	invitationRepo := repository.NewInvitationRepository(gdb)
+
+	oauthProviders := map[string]*oidc.Provider{}
+	for _, p := range cfg.OAuthProviders {
+		oauthProviders[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+
+	var mail mailer.Mailer = mailer.NewLogMailer(cfg.MailFrom)
+	if cfg.MailDriver == "file" {
+		fm, err := mailer.NewFileMailer(cfg.MailFrom, cfg.MailDir)
+		if err != nil {
+			log.Fatal("file mailer:", err)
+		}
+		mail = fm
+	}
 
-	userSvc := service.NewUserSvc(userRepo, v)
-	authSvc := service.NewAuthSvc(userRepo, v, cfg.JWTSecret, cfg.JWTAccessTTL)
+	jwtKeys, err := loadJWTKeys(cfg)
+	if err != nil {
+		log.Fatal("jwt keys:", err)
+	}
+	jwtOpts := auth.TokenOptions{
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+		Leeway:   cfg.JWTLeeway,
+	}
+
+	var revocations repository.RevocationStore
+	if cfg.TokenRevocationStore == "memory" {
+		revocations = repository.NewMemoryRevocationStore()
+	} else {
+		revocations = repository.NewRevocationStore(gdb)
+	}
+
+	roleSvc := service.NewRoleSvc(roleRepo, userRepo, revocations)
+	if err := roleSvc.Bootstrap(context.Background(), cfg.AdminEmail); err != nil {
+		log.Fatal("bootstrap roles:", err)
+	}
+
+	mfaSvc := service.NewMFASvc(mfaRepo, userRepo, service.MFAConfig{
+		Issuer:        cfg.MFAIssuer,
+		EncryptionKey: cfg.MFAEncryptionKey,
+	})
+
+	loginGuard := service.NewLoginGuard(loginAttemptRepo, service.LoginGuardConfig{
+		MaxFailures:     cfg.LoginMaxFailures,
+		LockoutDuration: cfg.LoginLockoutDuration,
+		IPMaxFailures:   cfg.LoginIPMaxFailures,
+		Window:          cfg.LoginFailureWindow,
+		DelayAfter:      cfg.LoginDelayAfter,
+		DelayBase:       cfg.LoginDelayBase,
+	})
+
+	passwordPolicy := &password.Policy{
+		MinLength:        cfg.PasswordMinLength,
+		MaxLength:        cfg.PasswordMaxLength,
+		RequireUpper:     cfg.PasswordRequireUpper,
+		RequireLower:     cfg.PasswordRequireLower,
+		RequireDigit:     cfg.PasswordRequireDigit,
+		RequireSymbol:    cfg.PasswordRequireSymbol,
+		DisallowUserInfo: cfg.PasswordDisallowUserInfo,
+		MinStrength:      cfg.PasswordMinStrength,
+	}
+	if cfg.PasswordBreachedDir != "" {
+		passwordPolicy.Breached = password.NewRangeDir(cfg.PasswordBreachedDir)
+	}
+
+	hasher, err := password.NewHasher(password.HasherConfig{
+		Algorithm: cfg.PasswordHashAlgorithm,
+		Argon2: password.Argon2Params{
+			Memory:      uint32(cfg.PasswordArgon2Memory),
+			Iterations:  uint32(cfg.PasswordArgon2Iterations),
+			Parallelism: uint8(cfg.PasswordArgon2Threads),
+			SaltLength:  password.DefaultArgon2Params.SaltLength,
+			KeyLength:   password.DefaultArgon2Params.KeyLength,
+		},
+		BcryptCost: cfg.PasswordBcryptCost,
+	})
+	if err != nil {
+		log.Fatal("password hasher:", err)
+	}
+	if cfg.PasswordHashAlgorithm == password.AlgBcrypt {
+		// bcrypt menolak password > 72 byte; ditolak lebih awal sebagai error validasi
+		passwordPolicy.MaxBytes = password.BcryptMaxBytes
+	}
+
+	apiKeySvc := service.NewAPIKeySvc(apiKeyRepo, userRepo, roleRepo)
+	sessionSvc := service.NewSessionSvc(sessionRepo, refreshRepo)
+	impersonationSvc := service.NewImpersonationSvc(userRepo, roleRepo, auditRepo, service.ImpersonationConfig{
+		JWTKeys:    jwtKeys,
+		JWTOptions: jwtOpts,
+		TTL:        cfg.ImpersonationTTL,
+	})
+	invitationSvc := service.NewInvitationSvc(service.InvitationDeps{
+		Invitations: invitationRepo,
+		Users:       userRepo,
+		Roles:       roleRepo,
+		Tx:          repository.NewTransactor(gdb),
+		Mailer:      mail,
+		Passwords:   passwordPolicy,
+		Hasher:      hasher,
+	}, v, service.InvitationConfig{
+		AppBaseURL: cfg.AppBaseURL,
+		TTL:        cfg.InvitationTTL,
+	})
+	userSvc := service.NewUserSvc(userRepo, revocations, cursor.New(cfg.PaginationCursorSecret), v)
+	if cfg.UserPurgeInterval > 0 {
+		go service.RunUserPurge(context.Background(), userSvc, cfg.UserPurgeInterval, cfg.UserRetention)
+	}
+	authSvc := service.NewAuthSvc(service.AuthDeps{
+		Users:         userRepo,
+		RefreshTokens: refreshRepo,
+		Revocations:   revocations,
+		Roles:         roleRepo,
+		ActionTokens:  actionTokenRepo,
+		Mailer:        mail,
+		MFA:           mfaSvc,
+		Identities:    identityRepo,
+		Sessions:      sessionRepo,
+		Passwords:     passwordPolicy,
+		Hasher:        hasher,
+		LoginGuard:    loginGuard,
+		OAuth:         oauthProviders,
+	}, v, service.AuthConfig{
+		JWTKeys:          jwtKeys,
+		JWTOptions:       jwtOpts,
+		AccessTTL:        cfg.JWTAccessTTL,
+		RefreshTTL:       cfg.JWTRefreshTTL,
+		AppBaseURL:       cfg.AppBaseURL,
+		PasswordResetTTL: cfg.PasswordResetTTL,
+
+		EmailVerifyTTL:     cfg.EmailVerifyTTL,
+		VerificationPolicy: cfg.EmailVerificationPolicy,
+
+		MFAChallengeTTL: cfg.MFAChallengeTTL,
+
+		MagicLinkTTL: cfg.MagicLinkTTL,
+	})
 
 	userH := handler.NewUserHandler(userSvc)
 	authH := handler.NewAuthHandler(authSvc)
+	roleH := handler.NewRoleHandler(roleSvc)
+	mfaH := handler.NewMFAHandler(mfaSvc)
+	jwksH := handler.NewJWKSHandler(jwtKeys)
+	apiKeyH := handler.NewAPIKeyHandler(apiKeySvc)
+	sessionH := handler.NewSessionHandler(sessionSvc)
+	impersonationH := handler.NewImpersonationHandler(impersonationSvc)
+	invitationH := handler.NewInvitationHandler(invitationSvc)
 
 	// router (public + protected)
-	r := transport.NewRouter(userH, authH, cfg.JWTSecret, gdb)
+	mw := transport.Middlewares{
+		Auth: middleware.AuthBearer(middleware.AuthDeps{
+			Keys:        jwtKeys,
+			Options:     jwtOpts,
+			Revocations: revocations,
+			APIKeys:     apiKeySvc,
+			Sessions:    sessionSvc,
+			Audit:       auditRepo,
+			Accounts:    authSvc,
+			// admin yang di-suspend / kehilangan users:impersonate tidak bisa lagi memakai token impersonation
+			Impersonation: impersonationSvc,
+		}),
+	}
+	if cfg.UserRequireIfMatch {
+		mw.IfMatch = middleware.RequireIfMatch()
+	}
+	if cfg.EmailVerificationPolicy == service.VerifyPolicyRestrict {
+		mw.VerifiedEmail = middleware.RequireVerifiedEmail()
+	}
+	r := transport.NewRouter(transport.Handlers{
+		User:          userH,
+		Auth:          authH,
+		Role:          roleH,
+		MFA:           mfaH,
+		JWKS:          jwksH,
+		APIKey:        apiKeyH,
+		Session:       sessionH,
+		Impersonation: impersonationH,
+		Invitation:    invitationH,
+	}, mw, roleSvc, gdb)
 
 	log.Printf("listening at :%s", cfg.AppPort)
+	// IP client (lockout per IP, sesi, audit) hanya diambil dari X-Forwarded-For jika dikirim proxy di TRUSTED_PROXIES
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("trusted proxies:", err)
+	}
 	if err := r.Run(":" + cfg.AppPort); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// loadJWTKeys membaca key PEM dari JWT_SIGNING_KEYS; tanpa itu token di-sign HS256 dengan JWT_SECRET.
+func loadJWTKeys(cfg *config.Config) (*auth.KeySet, error) {
+	if len(cfg.JWTSigningKeys) == 0 {
+		return auth.NewKeySet(cfg.JWTKeyGrace, auth.NewHMACKey("default", cfg.JWTSecret))
+	}
+	keys := make([]*auth.Key, 0, len(cfg.JWTSigningKeys))
+	for _, kc := range cfg.JWTSigningKeys {
+		k, err := auth.LoadPEMKey(kc.ID, kc.Path)
+		if err != nil {
+			return nil, err
+		}
+		k.ActiveFrom = kc.ActiveFrom
+		keys = append(keys, k)
+	}
+	ks, err := auth.NewKeySet(cfg.JWTKeyGrace, keys...)
+	if err != nil {
+		return nil, err
+	}
+	// minimal satu key private yang sudah aktif, kalau tidak login tidak bisa menerbitkan token
+	if _, err := ks.SigningKey(time.Now()); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
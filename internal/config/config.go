@@ -2,17 +2,116 @@ package config
 
 import (
 	"log"
+	"net"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	AppPort      string
-	DSN          string
-	JWTSecret    string
-	JWTAccessTTL time.Duration
+	AppPort       string
+	DSN           string
+	JWTSecret     string
+	JWTAccessTTL  time.Duration
+	JWTRefreshTTL time.Duration
+
+	// proxy (IP / CIDR) yang boleh mengisi X-Forwarded-For / X-Real-IP; kosong = IP koneksi langsung
+	TrustedProxies []string
+
+	// key asimetris dari JWT_SIGNING_KEYS (lihat loadJWTKeys); kosong = HS256 dengan JWTSecret
+	JWTSigningKeys []JWTKey
+	// lama key lama tetap diterima setelah digantikan key baru
+	JWTKeyGrace time.Duration
+	// claim iss/aud yang dipasang & diwajibkan; kosong = tidak dicek
+	JWTIssuer   string
+	JWTAudience string
+	// toleransi selisih jam antar server untuk exp/nbf/iat
+	JWTLeeway time.Duration
+
+	// "postgres" (default, aman untuk multi instance) atau "memory"
+	TokenRevocationStore string
+
+	// email user yang otomatis diberi role admin saat startup (opsional)
+	AdminEmail string
+
+	// URL frontend, dipakai untuk link di email (reset password, dsb)
+	AppBaseURL       string
+	PasswordResetTTL time.Duration
+	MagicLinkTTL     time.Duration
+	InvitationTTL    time.Duration
+
+	EmailVerifyTTL time.Duration
+	// "off" (default), "login" (tolak login) atau "restrict" (tolak route selain /me)
+	EmailVerificationPolicy string
+
+	MFAIssuer        string        // nama aplikasi di authenticator app
+	MFAEncryptionKey string        // kunci enkripsi secret TOTP; default JWT_SECRET
+	MFAChallengeTTL  time.Duration // umur challenge antara login password dan kode 2FA
+
+	// proteksi brute-force login (lihat service.LoginGuardConfig)
+	LoginMaxFailures     int
+	LoginLockoutDuration time.Duration
+	LoginIPMaxFailures   int
+	LoginFailureWindow   time.Duration
+	LoginDelayAfter      int
+	LoginDelayBase       time.Duration
+
+	// kebijakan password baru (lihat password.Policy)
+	PasswordMinLength        int
+	PasswordMaxLength        int
+	PasswordRequireUpper     bool
+	PasswordRequireLower     bool
+	PasswordRequireDigit     bool
+	PasswordRequireSymbol    bool
+	PasswordDisallowUserInfo bool
+	PasswordMinStrength      int    // 0..4
+	PasswordBreachedDir      string // kosong = hanya daftar password umum bawaan
+
+	// hash password baru: "argon2id" (default) atau "bcrypt"; hash lama di-rehash otomatis saat login
+	PasswordHashAlgorithm    string
+	PasswordArgon2Memory     int // KiB
+	PasswordArgon2Iterations int
+	PasswordArgon2Threads    int
+	PasswordBcryptCost       int
+
+	// user yang di-soft delete dihapus permanen setelah UserRetention; dicek tiap UserPurgeInterval (0 = nonaktif)
+	UserRetention     time.Duration
+	UserPurgeInterval time.Duration
+
+	// kunci HMAC cursor pagination (mode keyset); default JWT_SECRET
+	PaginationCursorSecret string
+
+	// true (default) = PUT/PATCH/DELETE /api/v1/users/:id wajib menyertakan If-Match (ETag dari GET)
+	UserRequireIfMatch bool
+
+	// umur token impersonation admin (tanpa refresh token)
+	ImpersonationTTL time.Duration
+
+	// mailer: "log" (default) atau "file" (tulis .eml ke MailDir)
+	MailDriver string
+	MailFrom   string
+	MailDir    string
+
+	// provider OIDC dari OAUTH_PROVIDERS (lihat loadOAuthProviders)
+	OAuthProviders []OAuthProvider
+}
+
+type JWTKey struct {
+	ID         string
+	Path       string
+	ActiveFrom time.Time
+}
+
+type OAuthProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func Load() *Config {
@@ -32,6 +131,30 @@ func Load() *Config {
 
 	jwtSecret := mustEnv("JWT_SECRET")
 	jwtAccessTTL := mustDuration("JWT_ACCESS_TTL", "15m")
+	jwtRefreshTTL := mustDuration("JWT_REFRESH_TTL", "720h")
+
+	revocationStore := os.Getenv("TOKEN_REVOCATION_STORE")
+	if revocationStore == "" {
+		revocationStore = "postgres"
+	}
+	if revocationStore != "postgres" && revocationStore != "memory" {
+		log.Fatalf("invalid TOKEN_REVOCATION_STORE: %s (postgres|memory)", revocationStore)
+	}
+
+	mailDriver := envOr("MAIL_DRIVER", "log")
+	if mailDriver != "log" && mailDriver != "file" {
+		log.Fatalf("invalid MAIL_DRIVER: %s (log|file)", mailDriver)
+	}
+
+	hashAlg := envOr("PASSWORD_HASH_ALGORITHM", "argon2id")
+	if hashAlg != "argon2id" && hashAlg != "bcrypt" {
+		log.Fatalf("invalid PASSWORD_HASH_ALGORITHM: %s (argon2id|bcrypt)", hashAlg)
+	}
+
+	verifyPolicy := envOr("EMAIL_VERIFICATION_POLICY", "off")
+	if verifyPolicy != "off" && verifyPolicy != "login" && verifyPolicy != "restrict" {
+		log.Fatalf("invalid EMAIL_VERIFICATION_POLICY: %s (off|login|restrict)", verifyPolicy)
+	}
 
 	dsn := "host=" + host +
 		" user=" + user +
@@ -41,10 +164,73 @@ func Load() *Config {
 		" sslmode=" + ssl
 
 	cfg := &Config{
-		AppPort:      appPort,
-		DSN:          dsn,
-		JWTSecret:    jwtSecret,
-		JWTAccessTTL: jwtAccessTTL,
+		AppPort:       appPort,
+		DSN:           dsn,
+		JWTSecret:     jwtSecret,
+		JWTAccessTTL:  jwtAccessTTL,
+		JWTRefreshTTL: jwtRefreshTTL,
+
+		TrustedProxies: loadTrustedProxies(),
+
+		JWTSigningKeys: loadJWTKeys(),
+		JWTKeyGrace:    mustDuration("JWT_KEY_GRACE", "24h"),
+		JWTIssuer:      envOr("JWT_ISSUER", "gin-boilerplate"),
+		JWTAudience:    envOr("JWT_AUDIENCE", "gin-boilerplate-api"),
+		JWTLeeway:      mustDuration("JWT_LEEWAY", "30s"),
+
+		TokenRevocationStore: revocationStore,
+
+		AdminEmail: os.Getenv("ADMIN_EMAIL"),
+
+		AppBaseURL:       strings.TrimRight(envOr("APP_BASE_URL", "http://localhost:"+appPort), "/"),
+		PasswordResetTTL: mustDuration("PASSWORD_RESET_TTL", "30m"),
+		MagicLinkTTL:     mustDuration("MAGIC_LINK_TTL", "15m"),
+		InvitationTTL:    mustDuration("INVITATION_TTL", "168h"),
+
+		EmailVerifyTTL:          mustDuration("EMAIL_VERIFY_TTL", "48h"),
+		EmailVerificationPolicy: verifyPolicy,
+
+		MFAIssuer:        envOr("MFA_ISSUER", "gin-boilerplate"),
+		MFAEncryptionKey: envOr("MFA_ENCRYPTION_KEY", jwtSecret),
+		MFAChallengeTTL:  mustDuration("MFA_CHALLENGE_TTL", "5m"),
+
+		OAuthProviders: loadOAuthProviders(appPort),
+
+		LoginMaxFailures:     mustInt("LOGIN_MAX_FAILURES", 5),
+		LoginLockoutDuration: mustDuration("LOGIN_LOCKOUT_DURATION", "15m"),
+		LoginIPMaxFailures:   mustInt("LOGIN_IP_MAX_FAILURES", 50),
+		LoginFailureWindow:   mustDuration("LOGIN_FAILURE_WINDOW", "15m"),
+		LoginDelayAfter:      mustInt("LOGIN_DELAY_AFTER", 3),
+		LoginDelayBase:       mustDuration("LOGIN_DELAY_BASE", "1s"),
+
+		PasswordMinLength:        mustInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordMaxLength:        mustInt("PASSWORD_MAX_LENGTH", 72),
+		PasswordRequireUpper:     mustBool("PASSWORD_REQUIRE_UPPER", false),
+		PasswordRequireLower:     mustBool("PASSWORD_REQUIRE_LOWER", false),
+		PasswordRequireDigit:     mustBool("PASSWORD_REQUIRE_DIGIT", false),
+		PasswordRequireSymbol:    mustBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordDisallowUserInfo: mustBool("PASSWORD_DISALLOW_USER_INFO", true),
+		PasswordMinStrength:      mustInt("PASSWORD_MIN_STRENGTH", 2),
+		PasswordBreachedDir:      os.Getenv("PASSWORD_BREACHED_DIR"),
+
+		PasswordHashAlgorithm:    hashAlg,
+		PasswordArgon2Memory:     mustInt("PASSWORD_ARGON2_MEMORY", 64*1024),
+		PasswordArgon2Iterations: mustInt("PASSWORD_ARGON2_ITERATIONS", 3),
+		PasswordArgon2Threads:    mustInt("PASSWORD_ARGON2_THREADS", 2),
+		PasswordBcryptCost:       mustInt("PASSWORD_BCRYPT_COST", 10),
+
+		UserRetention:     mustDuration("USER_RETENTION", "720h"),
+		UserPurgeInterval: mustDuration("USER_PURGE_INTERVAL", "1h"),
+
+		PaginationCursorSecret: envOr("PAGINATION_CURSOR_SECRET", jwtSecret),
+
+		UserRequireIfMatch: mustBool("USER_REQUIRE_IF_MATCH", true),
+
+		ImpersonationTTL: mustDuration("IMPERSONATION_TTL", "15m"),
+
+		MailDriver: mailDriver,
+		MailFrom:   envOr("MAIL_FROM", "no-reply@example.com"),
+		MailDir:    envOr("MAIL_DIR", "./tmp/mail"),
 	}
 
 	log.Printf("config loaded")
@@ -60,6 +246,81 @@ func mustEnv(key string) string {
 	return val
 }
 
+// loadJWTKeys membaca JWT_SIGNING_KEYS=kid=path[@waktu_aktif_RFC3339],...
+// contoh: "2026-01=/keys/2026-01.pem,2026-04=/keys/2026-04.pem@2026-04-01T00:00:00Z"
+func loadJWTKeys() []JWTKey {
+	var out []JWTKey
+	for _, entry := range strings.Split(os.Getenv("JWT_SIGNING_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, rest, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || rest == "" {
+			log.Fatalf("invalid JWT_SIGNING_KEYS entry: %s (kid=path[@RFC3339])", entry)
+		}
+		k := JWTKey{ID: kid, Path: rest}
+		if path, from, ok := strings.Cut(rest, "@"); ok {
+			t, err := time.Parse(time.RFC3339, from)
+			if err != nil {
+				log.Fatalf("invalid activation time for JWT key %s: %v", kid, err)
+			}
+			k.Path, k.ActiveFrom = path, t
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+// loadTrustedProxies membaca TRUSTED_PROXIES=10.0.0.0/8,192.168.1.10
+func loadTrustedProxies() []string {
+	var out []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			log.Fatalf("invalid TRUSTED_PROXIES entry: %s (IP atau CIDR)", p)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// loadOAuthProviders membaca OAUTH_PROVIDERS=google,mock lalu untuk tiap nama:
+// OAUTH_<NAMA>_ISSUER, _CLIENT_ID, _CLIENT_SECRET (opsional), _REDIRECT_URL (opsional), _SCOPES (opsional, dipisah spasi)
+func loadOAuthProviders(appPort string) []OAuthProvider {
+	var out []OAuthProvider
+	for _, name := range strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+		p := OAuthProvider{
+			Name:         name,
+			Issuer:       mustEnv(prefix + "ISSUER"),
+			ClientID:     mustEnv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  envOr(prefix+"REDIRECT_URL", "http://localhost:"+appPort+"/auth/oauth/"+name+"/callback"),
+		}
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			p.Scopes = strings.Fields(scopes)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// helper env dengan nilai default
+func envOr(key, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
 // helper parse durasi (contoh: "15m", "1h")
 func mustDuration(key, def string) time.Duration {
 	val := os.Getenv(key)
@@ -72,3 +333,29 @@ func mustDuration(key, def string) time.Duration {
 	}
 	return d
 }
+
+// helper parse int dengan default
+func mustInt(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		log.Fatalf("invalid int for %s: %s", key, val)
+	}
+	return n
+}
+
+// helper parse bool dengan default
+func mustBool(key string, def bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Fatalf("invalid bool for %s: %s", key, val)
+	}
+	return b
+}
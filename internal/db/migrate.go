@@ -10,9 +10,11 @@ import (
 	"gorm.io/gorm"
 )
 
+//go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// RunMigrations menjalankan file SQL di db/migrations
+// RunMigrations menjalankan file SQL di internal/db/migrations. Dijalankan setelah AutoMigrate;
+// isinya hal yang tidak bisa dinyatakan lewat tag GORM (extension, index ekspresi, dsb).
 func RunMigrations(gdb *gorm.DB) {
 	sqlDB, err := gdb.DB()
 	if err != nil {
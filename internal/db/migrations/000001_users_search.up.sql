@@ -0,0 +1,11 @@
+-- pencarian user (lihat repository.scopeSearch):
+-- trigram untuk substring / toleran typo, full-text untuk prefix kata
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- dipakai juga oleh pencarian default LOWER(name) LIKE ...
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (lower(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (lower(email) gin_trgm_ops);
+
+-- ekspresi harus sama persis dengan repository.userSearchVector
+CREATE INDEX IF NOT EXISTS idx_users_search_tsv ON users
+    USING gin (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, '')));
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tujuan (purpose) token sekali pakai
+const (
+	TokenPurposePasswordReset = "password_reset"
+	TokenPurposeEmailVerify   = "email_verify"
+	TokenPurposeMFAChallenge  = "mfa_challenge"
+	TokenPurposeEmailChange   = "email_change" // Target = alamat email baru
+	TokenPurposeMagicLink     = "magic_link"   // Target = email user saat link dikirim
+)
+
+// ActionToken = token sekali pakai yang dikirim lewat email (reset password, dsb).
+// Hanya hash-nya yang disimpan; token dianggap terpakai setelah UsedAt terisi.
+type ActionToken struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	Purpose   string    `json:"purpose" gorm:"size:40;index;not null"`
+	TokenHash string    `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	// alamat email yang diverifikasi (email_verify / magic_link: token tidak berlaku jika email user
+	// sudah berubah; email_change: alamat baru yang akan dipasang)
+	Target    string     `json:"target,omitempty" gorm:"size:180"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"` // percobaan gagal (mis. kode MFA salah)
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *ActionToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix = awalan semua API key, memudahkan secret scanner mengenali key yang bocor
+const APIKeyPrefix = "gbk_"
+
+// APIKey = personal API key milik user untuk akses mesin (script, CI).
+// Key lengkap hanya ditampilkan sekali saat dibuat; yang disimpan hanya hash-nya.
+type APIKey struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	Name   string    `json:"name" gorm:"size:100;not null"`
+	// Prefix = bagian awal key (mis. "gbk_1a2b3c4d") untuk identifikasi di UI/log
+	Prefix  string `json:"prefix" gorm:"size:20;uniqueIndex;not null"`
+	KeyHash string `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	// Scopes membatasi permission key; kosong = sama dengan permission user
+	Scopes     []string   `json:"scopes" gorm:"serializer:json;type:jsonb"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	User       User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Aksi audit log
+const (
+	AuditImpersonationStart   = "impersonation.start"
+	AuditImpersonationRequest = "impersonation.request"
+)
+
+// AuditLog mencatat tindakan yang dilakukan actor atas nama user lain (impersonation).
+type AuditLog struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	Action    string    `json:"action" gorm:"size:60;index;not null"`
+	ActorID   uuid.UUID `json:"actor_id" gorm:"type:uuid;index;not null"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"` // user yang di-impersonate
+	TokenID   string    `json:"token_id,omitempty" gorm:"size:64;index"` // jti token impersonation
+	Method    string    `json:"method,omitempty" gorm:"size:10"`
+	Path      string    `json:"path,omitempty" gorm:"size:512"`
+	Status    int       `json:"status,omitempty"`
+	IP        string    `json:"ip" gorm:"size:64"`
+	UserAgent string    `json:"user_agent" gorm:"size:512"`
+	Detail    string    `json:"detail,omitempty" gorm:"size:500"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserIdentity menghubungkan akun lokal dengan akun di identity provider eksternal (OIDC).
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	Provider  string    `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string    `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject"`
+	Email     string    `json:"email" gorm:"size:180"`
+	User      User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (i *UserIdentity) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
+
+// OAuthState menyimpan state, nonce dan PKCE verifier antara /start dan /callback.
+type OAuthState struct {
+	StateHash    string    `json:"-" gorm:"size:64;primaryKey"`
+	Provider     string    `json:"provider" gorm:"size:50;not null"`
+	Nonce        string    `json:"-" gorm:"size:100;not null"`
+	CodeVerifier string    `json:"-" gorm:"size:100;not null"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index;not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
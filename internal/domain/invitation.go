@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invitation = undangan admin untuk membuat akun. Token hanya disimpan hash-nya; user baru
+// dibuat saat undangan diterima (POST /auth/invitations/accept) dengan nama & password sendiri.
+type Invitation struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	Email      string     `json:"email" gorm:"size:180;index;not null"`
+	Name       string     `json:"name,omitempty" gorm:"size:120"` // usulan nama, boleh diganti saat accept
+	Role       string     `json:"role,omitempty" gorm:"size:60"`  // role tambahan selain role default
+	InvitedBy  uuid.UUID  `json:"invited_by" gorm:"type:uuid;index;not null"`
+	TokenHash  string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	SentAt     time.Time  `json:"sent_at" gorm:"not null"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	UserID     *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"` // user yang dibuat saat accept
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (i *Invitation) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// LoginAttempt = hitungan login gagal per kunci ("acct:<email>" atau "ip:<ip>").
+// Failures di-reset jika gagal terakhir sudah lewat dari window.
+type LoginAttempt struct {
+	Key          string     `json:"key" gorm:"size:320;primaryKey"`
+	Failures     int        `json:"failures" gorm:"not null;default:0"`
+	LastFailedAt time.Time  `json:"last_failed_at" gorm:"not null"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserMFA = konfigurasi TOTP user. Aktif setelah ConfirmedAt terisi.
+type UserMFA struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	SecretEnc string    `json:"-" gorm:"size:255;not null"` // secret base32, terenkripsi (pkg/secretbox)
+	// step TOTP terakhir yang dipakai, kode pada step <= ini ditolak (anti replay)
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	User         User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (UserMFA) TableName() string { return "user_mfa" }
+
+// MFARecoveryCode = kode cadangan sekali pakai bila authenticator hilang
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	User      User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (c *MFARecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshToken menyimpan hash refresh token. Semua token hasil rotasi dari satu login
+// berbagi FamilyID, sehingga reuse token lama bisa mencabut seluruh keluarga.
+type RefreshToken struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	FamilyID     uuid.UUID  `json:"family_id" gorm:"type:uuid;index;not null"`
+	TokenHash    string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ReplacedByID *uuid.UUID `json:"replaced_by_id" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Nama role bawaan
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permission yang dikenal aplikasi. "*" = semua permission, "users:*" = semua aksi pada users.
+const (
+	PermAll         = "*"
+	PermUsersRead   = "users:read"
+	PermUsersWrite  = "users:write"
+	PermUsersDelete = "users:delete"
+	PermRolesRead   = "roles:read"
+	PermRolesWrite  = "roles:write"
+	// login sebagai user lain untuk support; user yang punya permission ini tidak bisa di-impersonate
+	PermUsersImpersonate = "users:impersonate"
+)
+
+// KnownPermissions = semua permission konkret di atas (dipakai validasi scope API key)
+var KnownPermissions = []string{
+	PermUsersRead, PermUsersWrite, PermUsersDelete, PermUsersImpersonate,
+	PermRolesRead, PermRolesWrite,
+}
+
+// PermissionMatches: "*" cocok dengan semua, "users:*" cocok dengan "users:read", dst.
+func PermissionMatches(granted, required string) bool {
+	if granted == PermAll || granted == required {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ":") {
+		return strings.HasPrefix(required, prefix)
+	}
+	return false
+}
+
+// DefaultRoles di-seed saat startup (idempotent)
+var DefaultRoles = map[string][]string{
+	RoleAdmin: {PermAll},
+	RoleUser:  {},
+}
+
+type Role struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey"`
+	Name        string           `json:"name" gorm:"size:60;uniqueIndex;not null"`
+	Description string           `json:"description,omitempty" gorm:"size:255"`
+	Permissions []RolePermission `json:"-" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func (r *Role) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+// PermissionNames mengembalikan daftar permission dalam bentuk string
+func (r *Role) PermissionNames() []string {
+	out := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		out = append(out, p.Permission)
+	}
+	return out
+}
+
+type RolePermission struct {
+	RoleID     uuid.UUID `json:"role_id" gorm:"type:uuid;primaryKey"`
+	Permission string    `json:"permission" gorm:"size:100;primaryKey"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	RoleID    uuid.UUID `json:"role_id" gorm:"type:uuid;primaryKey;index"`
+	Role      Role      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	User      User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session = satu login (perangkat/browser). ID sama dengan FamilyID refresh token-nya dan
+// dibawa access token sebagai claim "sid", sehingga session yang dicabut langsung ditolak.
+type Session struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	UserAgent  string     `json:"user_agent" gorm:"size:512"`
+	IP         string     `json:"ip" gorm:"size:64"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" gorm:"not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"` // ikut diperpanjang setiap refresh
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	User       User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+
+	// Current = session dari token yang sedang dipakai (diisi handler, tidak disimpan)
+	Current bool `json:"current" gorm:"-"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken = access token (berdasarkan jti) yang dicabut sebelum kedaluwarsa.
+// Baris boleh dihapus setelah ExpiresAt karena token-nya sudah tidak valid lagi.
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"column:jti;size:64;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserTokenRevocation menyimpan batas waktu per user: semua token dengan iat <= NotBefore ditolak.
+type UserTokenRevocation struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	NotBefore time.Time `json:"not_before" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -7,13 +7,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// Status akun. Suspended bisa berakhir otomatis (SuspendedUntil), disabled hanya dibuka admin.
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDisabled  = "disabled"
+)
+
 type User struct {
-	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;index:idx_users_created_at_id,priority:2"`
 	Name         string    `json:"name" gorm:"size:120;not null"`
-	Email        string    `json:"email" gorm:"size:180;uniqueIndex;not null"`
+	Email        string    `json:"email" gorm:"size:180;not null;uniqueIndex:idx_users_email_active,where:deleted_at IS NULL"`
 	PasswordHash *string   `json:"-"` // nullable utk user OAuth di masa depan
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// nil = email belum diverifikasi
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"index:idx_users_created_at_id,priority:1"` // keyset pagination default
+	UpdatedAt       time.Time  `json:"updated_at"`
+	// lihat UserStatus*; alasan hanya untuk admin, tidak dikirim ke user saat login ditolak
+	Status         string     `json:"status" gorm:"size:20;not null;default:active;index"`
+	StatusReason   string     `json:"-" gorm:"size:500"`         // hanya lewat dto.UserStatusResp (admin)
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"` // nil + suspended = sampai diaktifkan lagi
+	// naik setiap perubahan data user; dipakai sebagai ETag (optimistic concurrency lewat If-Match)
+	Version int64 `json:"version" gorm:"not null;default:1"`
+	// soft delete: terisi = user dihapus, di-purge permanen setelah masa retensi.
+	// Index unik email hanya berlaku untuk user yang belum dihapus.
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+
+	// diisi service dari tabel user_roles, bukan kolom
+	Roles []string `json:"roles,omitempty" gorm:"-"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
@@ -22,3 +43,14 @@ func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// EffectiveStatus memperhitungkan suspend yang sudah lewat SuspendedUntil sebagai active
+func (u *User) EffectiveStatus(now time.Time) string {
+	if u.Status == UserStatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return UserStatusActive
+	}
+	if u.Status == "" {
+		return UserStatusActive
+	}
+	return u.Status
+}
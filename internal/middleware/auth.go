@@ -1,38 +1,283 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
+	"errors"
+	"log"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/clientinfo"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
 )
 
-func AuthBearer(jwtSecret string) gin.HandlerFunc {
+// Nilai "auth_method" di context
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// APIKeyAuthenticator memvalidasi personal API key (lihat service.APIKeyService)
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, raw string) (*domain.APIKey, *domain.User, error)
+}
+
+// SessionChecker memastikan session (claim "sid") belum dicabut dan akun pemiliknya masih
+// aktif (lihat service.SessionService)
+type SessionChecker interface {
+	CheckSession(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// AccountChecker menolak user yang di-suspend / dinonaktifkan / dihapus (lihat service.AuthService)
+type AccountChecker interface {
+	AccountActive(ctx context.Context, userID uuid.UUID) error
+}
+
+// ImpersonationChecker memastikan admin pada token impersonation masih boleh impersonate
+// (lihat service.ImpersonationService)
+type ImpersonationChecker interface {
+	CheckActor(ctx context.Context, actorID uuid.UUID) error
+}
+
+type AuthDeps struct {
+	Keys        *auth.KeySet
+	Options     auth.TokenOptions
+	Revocations repository.RevocationStore    // nil = tanpa pengecekan revoke
+	APIKeys     APIKeyAuthenticator           // nil = API key tidak diterima
+	Sessions    SessionChecker                // nil = claim sid tidak dicek
+	Audit       repository.AuditLogRepository // nil = request impersonation tidak dicatat
+	Accounts    AccountChecker                // nil = status akun token tanpa sid tidak dicek
+	// nil = status dan permission admin pada token impersonation tidak dicek ulang
+	Impersonation ImpersonationChecker
+}
+
+// AuthBearer memvalidasi JWT (signature, exp/nbf/iat, iss/aud sesuai opts) dan mengecek
+// revocation store di setiap request. Token impersonation (claim "act") hanya diterima selama
+// admin-nya masih aktif dan berhak impersonate, dan setiap request-nya dicatat ke audit log
+// setelah request selesai. Jika d.APIKeys diset, API key lewat header X-API-Key
+// atau "Authorization: ApiKey <key>" juga diterima.
+func AuthBearer(d AuthDeps) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		hdr := c.GetHeader("Authorization")
-		if !strings.HasPrefix(hdr, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+		if raw, ok := apiKeyFromRequest(c); ok && d.APIKeys != nil {
+			authenticateAPIKey(c, d.APIKeys, raw)
+			return
+		}
+
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			abortWithError(c, apperr.Unauthorized("bearer token tidak ada", nil))
 			return
 		}
-		token := strings.TrimPrefix(hdr, "Bearer ")
-		claims, err := auth.Parse(token, jwtSecret)
+		claims, err := auth.Parse(token, d.Keys, d.Options)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			abortWithError(c, tokenError(err))
 			return
 		}
-		uid, err := uuid.Parse(claims.UserID)
+		uid, err := uuid.Parse(claims.Subject)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid uid"})
+			abortWithError(c, apperr.New("token_malformed", 401, "sub token tidak valid", err))
 			return
 		}
+
+		// cek token sudah di-logout / dicabut
+		if d.Revocations != nil {
+			revoked, err := d.Revocations.IsRevoked(c.Request.Context(), claims.ID, uid, claims.IssuedAt.Time)
+			if err != nil {
+				abortWithError(c, apperr.Internal("gagal mengecek token", err))
+				return
+			}
+			if revoked {
+				abortWithError(c, apperr.New("token_revoked", 401, "token sudah dicabut", nil))
+				return
+			}
+		}
+
+		var sessionID uuid.UUID
+		if claims.SessionID != "" {
+			sessionID, err = uuid.Parse(claims.SessionID)
+			if err != nil {
+				abortWithError(c, apperr.New("token_malformed", 401, "sid token tidak valid", err))
+				return
+			}
+		}
+		switch {
+		case sessionID != uuid.Nil && d.Sessions != nil:
+			// session dicabut (logout perangkat lain, dsb) atau akun di-suspend setelah token terbit;
+			// keduanya dicek dalam satu query
+			if err := d.Sessions.CheckSession(c.Request.Context(), sessionID, uid); err != nil {
+				abortWithError(c, err)
+				return
+			}
+		case d.Accounts != nil:
+			// token tanpa sid (mis. impersonation)
+			if err := d.Accounts.AccountActive(c.Request.Context(), uid); err != nil {
+				abortWithError(c, err)
+				return
+			}
+		}
+
+		var actorID uuid.UUID
+		if claims.Actor != nil {
+			actorID, err = uuid.Parse(claims.Actor.Subject)
+			if err != nil {
+				abortWithError(c, apperr.New("token_malformed", 401, "act token tidak valid", err))
+				return
+			}
+			// token impersonation ikut tidak berlaku jika token admin dicabut (logout-all, ganti
+			// password, role berubah) setelah token terbit
+			if d.Revocations != nil {
+				revoked, err := d.Revocations.IsRevoked(c.Request.Context(), "", actorID, claims.IssuedAt.Time)
+				if err != nil {
+					abortWithError(c, apperr.Internal("gagal mengecek token", err))
+					return
+				}
+				if revoked {
+					abortWithError(c, apperr.New("token_revoked", 401, "token sudah dicabut", nil))
+					return
+				}
+			}
+			if d.Impersonation != nil {
+				if err := d.Impersonation.CheckActor(c.Request.Context(), actorID); err != nil {
+					abortWithError(c, err)
+					return
+				}
+			}
+		}
+
 		// inject ke context
+		c.Set("auth_method", AuthMethodJWT)
 		c.Set("user_id", uid)
 		if claims.Email != "" {
 			c.Set("user_email", claims.Email)
 		}
+		c.Set("email_verified", claims.EmailVerified)
+		c.Set("user_roles", claims.Roles)
+		c.Set("token_jti", claims.ID)
+		c.Set("token_exp", claims.ExpiresAt.Time)
+		if sessionID != uuid.Nil {
+			c.Set("session_id", sessionID)
+		}
+		if actorID == uuid.Nil {
+			c.Next()
+			return
+		}
+		c.Set("impersonator_id", actorID)
+		c.Next()
+		recordImpersonatedRequest(c, d.Audit, actorID, uid, claims.ID)
+	}
+}
+
+// recordImpersonatedRequest mencatat request yang dilakukan dengan token impersonation,
+// termasuk yang ditolak (status 4xx). Gagal mencatat hanya di-log karena response sudah terkirim.
+func recordImpersonatedRequest(c *gin.Context, audit repository.AuditLogRepository, actorID, userID uuid.UUID, jti string) {
+	if audit == nil {
+		return
+	}
+	// tetap dicatat walau client sudah memutus koneksi
+	ctx := context.WithoutCancel(c.Request.Context())
+	ci := clientinfo.From(ctx)
+	entry := &domain.AuditLog{
+		Action:    domain.AuditImpersonationRequest,
+		ActorID:   actorID,
+		UserID:    userID,
+		TokenID:   jti,
+		Method:    c.Request.Method,
+		Path:      clientinfo.Truncate(c.Request.URL.RequestURI(), 512),
+		Status:    c.Writer.Status(),
+		IP:        ci.IP,
+		UserAgent: clientinfo.Truncate(ci.UserAgent, 512),
+	}
+	if err := audit.Create(ctx, entry); err != nil {
+		log.Printf("audit impersonation %s %s: %v", entry.Method, entry.Path, err)
+	}
+}
+
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, raw string) {
+	k, u, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), raw)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Set("auth_method", AuthMethodAPIKey)
+	c.Set("user_id", u.ID)
+	c.Set("user_email", u.Email)
+	c.Set("email_verified", u.EmailVerifiedAt != nil)
+	c.Set("user_roles", u.Roles)
+	c.Set("api_key_id", k.ID)
+	c.Set("api_key_scopes", k.Scopes)
+	c.Next()
+}
+
+func apiKeyFromRequest(c *gin.Context) (string, bool) {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key, true
+	}
+	if key, ok := strings.CutPrefix(c.GetHeader("Authorization"), "ApiKey "); ok && key != "" {
+		return key, true
+	}
+	return "", false
+}
+
+// BlockImpersonation menolak token impersonation untuk aksi sensitif (ganti password/email,
+// API key, 2FA, session) yang hanya boleh dilakukan pemilik akun sendiri.
+func BlockImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("impersonator_id"); ok {
+			abortWithError(c, apperr.New("impersonation_forbidden", 403, "aksi ini tidak bisa dilakukan saat impersonate user", nil))
+			return
+		}
 		c.Next()
 	}
 }
+
+// RequireUserToken menolak request yang diautentikasi dengan API key, untuk route sensitif
+// (kelola API key, 2FA, logout) yang hanya boleh dilakukan user yang login langsung.
+func RequireUserToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != AuthMethodJWT {
+			abortWithError(c, apperr.Forbidden("route ini tidak bisa diakses dengan api key", nil))
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(hdr string) (string, bool) {
+	if !strings.HasPrefix(hdr, "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimPrefix(hdr, "Bearer ")
+	return token, token != ""
+}
+
+// tokenError memetakan error validasi JWT ke kode error yang bisa dibedakan client
+func tokenError(err error) *apperr.AppError {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return apperr.New("token_expired", 401, "token sudah kedaluwarsa", err)
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return apperr.New("token_not_yet_valid", 401, "token belum berlaku", err)
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return apperr.New("token_invalid_audience", 401, "audience token tidak sesuai", err)
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return apperr.New("token_invalid_issuer", 401, "issuer token tidak sesuai", err)
+	case errors.Is(err, jwt.ErrTokenMalformed), errors.Is(err, jwt.ErrTokenRequiredClaimMissing),
+		errors.Is(err, jwt.ErrTokenInvalidClaims):
+		return apperr.New("token_malformed", 401, "format token tidak valid", err)
+	default:
+		// signature salah, kid tidak dikenal, algoritma tidak diizinkan
+		return apperr.New("token_invalid", 401, "token tidak valid", err)
+	}
+}
+
+func abortWithError(c *gin.Context, err error) {
+	response.WriteError(c, err)
+	c.Abort()
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/pkg/clientinfo"
 )
 
 func Logger() gin.HandlerFunc {
@@ -18,8 +20,10 @@ func Logger() gin.HandlerFunc {
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, If-Match")
+		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+		// ETag dibaca client untuk dikirim balik lewat If-Match
+		c.Header("Access-Control-Expose-Headers", "ETag")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -27,3 +31,15 @@ func CORS() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ClientInfo menyimpan IP dan user agent ke context request (dibaca service via clientinfo.From)
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := clientinfo.With(c.Request.Context(), clientinfo.Info{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+// PermissionResolver memetakan role (dari claim token) ke permission
+type PermissionResolver interface {
+	HasPermission(ctx context.Context, roles []string, perm string) (bool, error)
+}
+
+// RequirePermission menolak request jika tidak ada role user yang memiliki perm.
+// Untuk API key dengan scope, perm juga harus tercakup salah satu scope.
+// Harus dipasang setelah AuthBearer (butuh "user_roles" di context).
+func RequirePermission(resolver PermissionResolver, perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := c.GetStringSlice("user_roles")
+		ok, err := resolver.HasPermission(c.Request.Context(), roles, perm)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check permission"})
+			return
+		}
+		if !ok || !scopeAllows(c.GetStringSlice("api_key_scopes"), perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// scopeAllows: tanpa scope (JWT atau API key tanpa batasan) = tidak membatasi
+func scopeAllows(scopes []string, perm string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, sc := range scopes {
+		if domain.PermissionMatches(sc, perm) {
+			return true
+		}
+	}
+	return false
+}
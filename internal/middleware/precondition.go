@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+// RequireIfMatch menolak request tanpa header If-Match (428) supaya client tidak bisa
+// menimpa perubahan orang lain tanpa sadar. Nilai header dicek oleh handler.
+func RequireIfMatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("If-Match") == "" {
+			response.WriteError(c, apperr.PreconditionRequired("header If-Match wajib diisi dengan ETag dari GET terakhir"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
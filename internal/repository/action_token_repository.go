@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type ActionTokenRepository interface {
+	Create(ctx context.Context, t *domain.ActionToken) error
+	// FindActive mencari token yang belum dipakai dan belum kedaluwarsa
+	FindActive(ctx context.Context, purpose, hash string) (*domain.ActionToken, error)
+	// Consume menandai token terpakai; false jika sudah dipakai request lain
+	Consume(ctx context.Context, id uuid.UUID) (bool, error)
+	// RecordFailedAttempt menambah hitungan gagal; token hangus setelah maxAttempts
+	RecordFailedAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error
+	// InvalidateForUser menandai semua token aktif user untuk purpose tsb sebagai terpakai
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+type actionTokenRepo struct{ db *gorm.DB }
+
+func NewActionTokenRepository(db *gorm.DB) ActionTokenRepository {
+	return &actionTokenRepo{db: db}
+}
+
+func (r *actionTokenRepo) Create(ctx context.Context, t *domain.ActionToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+func (r *actionTokenRepo) FindActive(ctx context.Context, purpose, hash string) (*domain.ActionToken, error) {
+	var t domain.ActionToken
+	err := r.db.WithContext(ctx).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > now()", purpose, hash).
+		First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *actionTokenRepo) Consume(ctx context.Context, id uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.ActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", gorm.Expr("now()"))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *actionTokenRepo) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ActionToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", gorm.Expr("now()")).Error
+}
+
+func (r *actionTokenRepo) RecordFailedAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]any{
+			"attempts": gorm.Expr("attempts + 1"),
+			"used_at":  gorm.Expr("CASE WHEN attempts + 1 >= ? THEN now() ELSE NULL END", maxAttempts),
+		}).Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *domain.APIKey) error
+	// FindActiveByHash mencari key yang belum dicabut dan belum kedaluwarsa
+	FindActiveByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	// FindByUser mengembalikan semua key user yang belum dicabut, terbaru dulu
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	// Revoke mencabut key milik user; false jika tidak ada key aktif dengan id tsb
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	// TouchLastUsed mencatat waktu pakai, paling sering sekali per menit per key
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyRepo struct{ db *gorm.DB }
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, k *domain.APIKey) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(k).Error
+}
+
+func (r *apiKeyRepo) FindActiveByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var k domain.APIKey
+	err := r.db.WithContext(ctx).
+		Where("key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())", hash).
+		First(&k).Error
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepo) FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	var out []domain.APIKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", gorm.Expr("now()"))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')", id).
+		Update("last_used_at", gorm.Expr("now()")).Error
+}
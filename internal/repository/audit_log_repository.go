@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, a *domain.AuditLog) error
+}
+
+type auditLogRepo struct{ db *gorm.DB }
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepo{db: db}
+}
+
+func (r *auditLogRepo) Create(ctx context.Context, a *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
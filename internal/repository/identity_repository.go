@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type IdentityRepository interface {
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error)
+	Create(ctx context.Context, i *domain.UserIdentity) error
+
+	SaveState(ctx context.Context, s *domain.OAuthState) error
+	// ConsumeState mengambil lalu menghapus state (sekali pakai); ErrRecordNotFound jika tidak ada/expired
+	ConsumeState(ctx context.Context, stateHash string) (*domain.OAuthState, error)
+}
+
+type identityRepo struct{ db *gorm.DB }
+
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepo{db: db}
+}
+
+func (r *identityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var i domain.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&i).Error
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *identityRepo) FindByUser(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	var out []domain.UserIdentity
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&out).Error
+	return out, err
+}
+
+func (r *identityRepo) Create(ctx context.Context, i *domain.UserIdentity) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(i).Error
+}
+
+func (r *identityRepo) SaveState(ctx context.Context, s *domain.OAuthState) error {
+	db := r.db.WithContext(ctx)
+	// housekeeping: state yang ditinggal user (tidak pernah callback)
+	if err := db.Where("expires_at < now()").Delete(&domain.OAuthState{}).Error; err != nil {
+		return err
+	}
+	return db.Create(s).Error
+}
+
+func (r *identityRepo) ConsumeState(ctx context.Context, stateHash string) (*domain.OAuthState, error) {
+	var out []domain.OAuthState
+	// DELETE ... RETURNING agar state tidak bisa dipakai dua kali secara bersamaan
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ? AND expires_at > now()", stateHash).
+		Delete(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &out[0], nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, inv *domain.Invitation) error
+	// FindPending = belum diterima, belum dicabut (boleh sudah kedaluwarsa, supaya bisa di-resend)
+	FindPending(ctx context.Context, id uuid.UUID) (*domain.Invitation, error)
+	// FindPendingByEmail mencari undangan yang masih bisa diterima untuk email tsb
+	FindPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error)
+	// FindUsableByHash = undangan yang belum diterima, belum dicabut dan belum kedaluwarsa
+	FindUsableByHash(ctx context.Context, hash string) (*domain.Invitation, error)
+	// ListPending mengembalikan undangan yang belum diterima / dicabut, terbaru dulu
+	ListPending(ctx context.Context) ([]domain.Invitation, error)
+	// Reissue mengganti token dan memperpanjang masa berlaku undangan pending
+	Reissue(ctx context.Context, id uuid.UUID, hash string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+	// MarkAccepted menandai undangan diterima (atomik); false jika sudah dipakai / dicabut / kedaluwarsa
+	MarkAccepted(ctx context.Context, id, userID uuid.UUID) (bool, error)
+}
+
+type invitationRepo struct{ db *gorm.DB }
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepo{db: db}
+}
+
+const invitationPending = "accepted_at IS NULL AND revoked_at IS NULL"
+
+func (r *invitationRepo) Create(ctx context.Context, inv *domain.Invitation) error {
+	return r.db.WithContext(ctx).Create(inv).Error
+}
+
+func (r *invitationRepo) FindPending(ctx context.Context, id uuid.UUID) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	if err := r.db.WithContext(ctx).Where("id = ? AND "+invitationPending, id).First(&inv).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *invitationRepo) FindPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := r.db.WithContext(ctx).
+		Where("email = ? AND expires_at > now() AND "+invitationPending, email).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *invitationRepo) FindUsableByHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND expires_at > now() AND "+invitationPending, hash).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *invitationRepo) ListPending(ctx context.Context) ([]domain.Invitation, error) {
+	var out []domain.Invitation
+	err := r.db.WithContext(ctx).
+		Where(invitationPending).
+		Order("created_at DESC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *invitationRepo) Reissue(ctx context.Context, id uuid.UUID, hash string, expiresAt time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("id = ? AND "+invitationPending, id).
+		Updates(map[string]any{
+			"token_hash": hash,
+			"expires_at": expiresAt,
+			"sent_at":    gorm.Expr("now()"),
+			"updated_at": gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *invitationRepo) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("id = ? AND "+invitationPending, id).
+		Updates(map[string]any{
+			"revoked_at": gorm.Expr("now()"),
+			"updated_at": gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *invitationRepo) MarkAccepted(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("id = ? AND expires_at > now() AND "+invitationPending, id).
+		Updates(map[string]any{
+			"accepted_at": gorm.Expr("now()"),
+			"user_id":     userID,
+			"updated_at":  gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type LoginAttemptRepository interface {
+	Find(ctx context.Context, key string) (*domain.LoginAttempt, error)
+	// RecordFailure menambah hitungan gagal secara atomik; hitungan mulai dari 1 lagi
+	// jika gagal terakhir lebih lama dari window. Mengembalikan state terbaru.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepo struct{ db *gorm.DB }
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepo{db: db}
+}
+
+func (r *loginAttemptRepo) Find(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var a domain.LoginAttempt
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *loginAttemptRepo) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error) {
+	var a domain.LoginAttempt
+	err := r.db.WithContext(ctx).Raw(`
+INSERT INTO login_attempts (key, failures, last_failed_at, updated_at)
+VALUES (?, 1, now(), now())
+ON CONFLICT (key) DO UPDATE SET
+	failures = CASE
+		WHEN login_attempts.last_failed_at < now() - make_interval(secs => ?) THEN 1
+		ELSE login_attempts.failures + 1
+	END,
+	last_failed_at = now(),
+	updated_at = now()
+RETURNING *`, key, window.Seconds()).Scan(&a).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *loginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r *loginAttemptRepo) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type MFARepository interface {
+	FindByUser(ctx context.Context, userID uuid.UUID) (*domain.UserMFA, error)
+	// Save membuat atau mengganti konfigurasi MFA user
+	Save(ctx context.Context, m *domain.UserMFA) error
+	Confirm(ctx context.Context, userID uuid.UUID, step int64) error
+	// UseStep mencatat step TOTP yang dipakai; false jika step tsb (atau lebih baru) sudah pernah dipakai
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// Delete menghapus MFA beserta recovery code user
+	Delete(ctx context.Context, userID uuid.UUID) error
+
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type mfaRepo struct{ db *gorm.DB }
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepo{db: db}
+}
+
+func (r *mfaRepo) FindByUser(ctx context.Context, userID uuid.UUID) (*domain.UserMFA, error) {
+	var m domain.UserMFA
+	if err := r.db.WithContext(ctx).First(&m, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mfaRepo) Save(ctx context.Context, m *domain.UserMFA) error {
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"secret_enc", "last_used_step", "confirmed_at", "updated_at"}),
+		}).
+		Create(m).Error
+}
+
+func (r *mfaRepo) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.UserMFA{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"confirmed_at":   gorm.Expr("now()"),
+			"last_used_step": step,
+			"updated_at":     gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *mfaRepo) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *mfaRepo) Delete(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.UserMFA{}).Error
+	})
+}
+
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]domain.MFARecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, domain.MFARecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Omit(clause.Associations).Create(&codes).Error
+	})
+}
+
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", gorm.Expr("now()"))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *mfaRepo) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&n).Error
+	return n, err
+}
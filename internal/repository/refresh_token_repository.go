@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, t *domain.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	// Rotate menandai token lama sebagai revoked dan menyimpan penggantinya dalam satu transaksi.
+	// Mengembalikan false jika token lama ternyata sudah direvoke (dipakai request lain).
+	Rotate(ctx context.Context, oldID uuid.UUID, next *domain.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	// RevokeOthersForUser mencabut semua token user kecuali family keepFamilyID
+	RevokeOthersForUser(ctx context.Context, userID, keepFamilyID uuid.UUID) error
+}
+
+var errAlreadyRotated = errors.New("refresh token already rotated")
+
+type refreshTokenRepo struct{ db *gorm.DB }
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepo{db: db}
+}
+
+func (r *refreshTokenRepo) Create(ctx context.Context, t *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+func (r *refreshTokenRepo) FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var t domain.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *refreshTokenRepo) Rotate(ctx context.Context, oldID uuid.UUID, next *domain.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		// conditional update: hanya satu request yang boleh merotasi token yang sama
+		res := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]any{
+				"revoked_at":     gorm.Expr("now()"),
+				"replaced_by_id": next.ID,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// batalkan token baru
+			return errAlreadyRotated
+		}
+		rotated = true
+		return nil
+	})
+	if errors.Is(err, errAlreadyRotated) {
+		return false, nil
+	}
+	return rotated, err
+}
+
+func (r *refreshTokenRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
+
+func (r *refreshTokenRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
+
+func (r *refreshTokenRepo) RevokeOthersForUser(ctx context.Context, userID, keepFamilyID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userID, keepFamilyID).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
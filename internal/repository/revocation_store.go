@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+// RevocationStore dipakai middleware untuk menolak access token yang sudah dicabut,
+// baik per token (jti) maupun seluruh token milik user (not_before).
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+	RevokeUser(ctx context.Context, userID uuid.UUID, notBefore time.Time) error
+	IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error)
+}
+
+// revokedByUser: iat di JWT berpresisi detik, jadi not_before dipotong ke detik yang sama.
+// Token yang terbit di detik pencabutan tetap diterima (mis. token baru setelah ganti password);
+// token yang terbit sebelum detik itu ditolak.
+func revokedByUser(issuedAt, notBefore time.Time) bool {
+	if notBefore.IsZero() {
+		return false
+	}
+	return issuedAt.Truncate(time.Second).Before(notBefore.Truncate(time.Second))
+}
+
+// =========================
+// In-memory (single instance / dev)
+// =========================
+
+type memoryRevocationStore struct {
+	mu        sync.RWMutex
+	tokens    map[string]time.Time // jti -> expires_at
+	notBefore map[uuid.UUID]time.Time
+}
+
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{
+		tokens:    map[string]time.Time{},
+		notBefore: map[uuid.UUID]time.Time{},
+	}
+}
+
+func (m *memoryRevocationStore) RevokeToken(_ context.Context, jti string, _ uuid.UUID, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// bersihkan entry yang sudah lewat masa berlaku
+	now := time.Now()
+	for k, exp := range m.tokens {
+		if now.After(exp) {
+			delete(m.tokens, k)
+		}
+	}
+	m.tokens[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryRevocationStore) RevokeUser(_ context.Context, userID uuid.UUID, notBefore time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notBefore[userID] = notBefore
+	return nil
+}
+
+func (m *memoryRevocationStore) IsRevoked(_ context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if jti != "" {
+		if _, ok := m.tokens[jti]; ok {
+			return true, nil
+		}
+	}
+	return revokedByUser(issuedAt, m.notBefore[userID]), nil
+}
+
+// =========================
+// Postgres (multi instance)
+// =========================
+
+type pgRevocationStore struct{ db *gorm.DB }
+
+func NewRevocationStore(db *gorm.DB) RevocationStore {
+	return &pgRevocationStore{db: db}
+}
+
+func (r *pgRevocationStore) RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+	db := r.db.WithContext(ctx)
+	// housekeeping ringan: jti yang sudah expired tidak perlu disimpan
+	if err := db.Where("expires_at < now()").Delete(&domain.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.RevokedToken{JTI: jti, UserID: userID, ExpiresAt: expiresAt}).Error
+}
+
+func (r *pgRevocationStore) RevokeUser(ctx context.Context, userID uuid.UUID, notBefore time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"not_before", "updated_at"}),
+		}).
+		Create(&domain.UserTokenRevocation{UserID: userID, NotBefore: notBefore}).Error
+}
+
+func (r *pgRevocationStore) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+	if jti != "" {
+		var n int64
+		if err := db.Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&n).Error; err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+
+	var rev domain.UserTokenRevocation
+	if err := db.First(&rev, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return revokedByUser(issuedAt, rev.NotBefore), nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRevokedByUser(t *testing.T) {
+	notBefore := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	tests := []struct {
+		name string
+		iat  time.Time
+		nb   time.Time
+		want bool
+	}{
+		{name: "tanpa pencabutan", iat: notBefore, nb: time.Time{}, want: false},
+		{name: "terbit detik sebelumnya", iat: time.Date(2024, 1, 2, 3, 4, 4, 0, time.UTC), nb: notBefore, want: true},
+		{name: "terbit di detik pencabutan", iat: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), nb: notBefore, want: false},
+		{name: "terbit setelah pencabutan", iat: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), nb: notBefore, want: false},
+		{name: "iat pecahan detik dari token lama", iat: time.Date(2024, 1, 2, 3, 4, 4, 900000000, time.UTC), nb: notBefore, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := revokedByUser(tt.iat, tt.nb); got != tt.want {
+				t.Fatalf("revokedByUser(%s, %s) = %v, want %v", tt.iat, tt.nb, got, tt.want)
+			}
+		})
+	}
+}
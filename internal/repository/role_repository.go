@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type RoleRepository interface {
+	// EnsureDefaults membuat role + permission yang belum ada (idempotent)
+	EnsureDefaults(ctx context.Context, defaults map[string][]string) error
+	FindAll(ctx context.Context) ([]domain.Role, error)
+	FindByName(ctx context.Context, name string) (*domain.Role, error)
+	RolesForUser(ctx context.Context, userID uuid.UUID) ([]domain.Role, error)
+	PermissionsForRoles(ctx context.Context, names []string) ([]string, error)
+	Grant(ctx context.Context, userID, roleID uuid.UUID) error
+	Revoke(ctx context.Context, userID, roleID uuid.UUID) error
+}
+
+type roleRepo struct{ db *gorm.DB }
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepo{db: db}
+}
+
+func (r *roleRepo) EnsureDefaults(ctx context.Context, defaults map[string][]string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for name, perms := range defaults {
+			role := domain.Role{Name: name}
+			if err := tx.Where("name = ?", name).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+			for _, p := range perms {
+				rp := domain.RolePermission{RoleID: role.ID, Permission: p}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rp).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *roleRepo) FindAll(ctx context.Context) ([]domain.Role, error) {
+	var out []domain.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&out).Error
+	return out, err
+}
+
+func (r *roleRepo) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepo) RolesForUser(ctx context.Context, userID uuid.UUID) ([]domain.Role, error) {
+	var out []domain.Role
+	err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		Joins("JOIN user_roles ur ON ur.role_id = roles.id").
+		Where("ur.user_id = ?", userID).
+		Order("roles.name").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *roleRepo) PermissionsForRoles(ctx context.Context, names []string) ([]string, error) {
+	var out []string
+	if len(names) == 0 {
+		return out, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.RolePermission{}).
+		Distinct("role_permissions.permission").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name IN ?", names).
+		Pluck("role_permissions.permission", &out).Error
+	return out, err
+}
+
+func (r *roleRepo) Grant(ctx context.Context, userID, roleID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit(clause.Associations).
+		Create(&domain.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+func (r *roleRepo) Revoke(ctx context.Context, userID, roleID uuid.UUID) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND role_id = ?", userID, roleID).
+		Delete(&domain.UserRole{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, s *domain.Session) error
+	// Extend memperpanjang session saat refresh; baris dibuat jika belum ada
+	// (session dari sebelum fitur ini ada). Session yang sudah dicabut tidak dihidupkan lagi.
+	Extend(ctx context.Context, s *domain.Session) error
+	// FindActiveByUser = session yang belum dicabut dan belum kedaluwarsa, terakhir dipakai dulu
+	FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	// FindActive = session milik userID yang belum dicabut / kedaluwarsa beserta status akun
+	// pemiliknya (satu query untuk setiap request); gorm.ErrRecordNotFound jika tidak ada / user dihapus
+	FindActive(ctx context.Context, id, userID uuid.UUID) (*ActiveSession, error)
+	// Touch memperbarui last_seen_at, paling sering sekali per menit per session
+	Touch(ctx context.Context, id uuid.UUID) error
+	// Revoke mencabut session milik user; false jika tidak ada session aktif dengan id tsb
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	// RevokeOthers mencabut semua session user kecuali keepID
+	RevokeOthers(ctx context.Context, userID, keepID uuid.UUID) error
+}
+
+// ActiveSession = hasil FindActive
+type ActiveSession struct {
+	LastSeenAt     time.Time
+	Status         string
+	SuspendedUntil *time.Time
+}
+
+type sessionRepo struct{ db *gorm.DB }
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+func (r *sessionRepo) Create(ctx context.Context, s *domain.Session) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(s).Error
+}
+
+func (r *sessionRepo) Extend(ctx context.Context, s *domain.Session) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"expires_at":   s.ExpiresAt,
+			"last_seen_at": s.LastSeenAt,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "sessions.revoked_at IS NULL"}}},
+	}).Omit(clause.Associations).Create(s).Error
+}
+
+func (r *sessionRepo) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	var out []domain.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > now()", userID).
+		Order("last_seen_at DESC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *sessionRepo) FindActive(ctx context.Context, id, userID uuid.UUID) (*ActiveSession, error) {
+	var out ActiveSession
+	err := r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Select("sessions.last_seen_at, users.status, users.suspended_until").
+		Joins("JOIN users ON users.id = sessions.user_id AND users.deleted_at IS NULL").
+		Where("sessions.id = ? AND sessions.user_id = ? AND sessions.revoked_at IS NULL AND sessions.expires_at > now()", id, userID).
+		Take(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *sessionRepo) Touch(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ? AND last_seen_at < ?", id, time.Now().Add(-time.Minute)).
+		Update("last_seen_at", gorm.Expr("now()")).Error
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", gorm.Expr("now()"))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *sessionRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
+
+func (r *sessionRepo) RevokeOthers(ctx context.Context, userID, keepID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TxRepos = repository yang semuanya memakai transaksi yang sama
+type TxRepos struct {
+	Users       UserRepository
+	Roles       RoleRepository
+	Invitations InvitationRepository
+}
+
+// Transactor menjalankan beberapa operasi repository secara atomik
+type Transactor interface {
+	// InTx: fn mengembalikan error = semua perubahan di dalamnya di-rollback; error diteruskan apa adanya
+	InTx(ctx context.Context, fn func(r TxRepos) error) error
+}
+
+type gormTransactor struct{ db *gorm.DB }
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+func (t *gormTransactor) InTx(ctx context.Context, fn func(r TxRepos) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(TxRepos{
+			Users:       NewUserRepository(tx),
+			Roles:       NewRoleRepository(tx),
+			Invitations: NewInvitationRepository(tx),
+		})
+	})
+}
@@ -2,26 +2,59 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/filter"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, u *domain.User) error
 	FindAll(ctx context.Context) ([]domain.User, error)
-	FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// FindByID: fields = nama field JSON yang diambil (lihat ParseUserFields); kosong = semua kolom
+	FindByID(ctx context.Context, id uuid.UUID, fields ...string) (*domain.User, error)
+	// Update menyimpan name & email hanya jika versi di DB masih u.Version (ErrVersionConflict jika
+	// sudah diubah request lain); berhasil = u.Version naik satu
 	Update(ctx context.Context, u *domain.User) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	FindPaged(ctx context.Context, q string, page, pageSize int, sortBy, sortDir string) ([]domain.User, int64, error)
+	// Delete = soft delete (deleted_at); gorm.ErrRecordNotFound jika user tidak ada / sudah dihapus.
+	// version > 0 = hanya jika versi user masih sama, selain itu ErrVersionConflict
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	// FindPaged, FindKeyset & CountFiltered mengembalikan *filter.Error jika filter / sort / fields tidak valid
+	FindPaged(ctx context.Context, f UserFilter, o UserListOptions, page, pageSize int) ([]domain.User, int64, error)
+	// FindKeyset = keyset pagination (tanpa OFFSET / COUNT), urut kolom sort lalu id
+	FindKeyset(ctx context.Context, f UserFilter, o UserListOptions, k UserKeyset) ([]domain.User, error)
+	CountFiltered(ctx context.Context, f UserFilter) (int64, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string) error
+	// ReplacePasswordHash mengganti hash hanya jika hash saat ini masih oldHash (dipakai saat rehash)
+	ReplacePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error
+	// MarkEmailVerified hanya berhasil jika email user masih sama dengan email yang diverifikasi
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error)
+	UpdateName(ctx context.Context, id uuid.UUID, name string) error
+	// ChangeEmail memasang email baru yang sudah diverifikasi (email_verified_at = now)
+	ChangeEmail(ctx context.Context, id uuid.UUID, email string) error
+	// UpdateStatus mengganti status akun beserta alasan & batas waktu suspend
+	UpdateStatus(ctx context.Context, id uuid.UUID, status, reason string, until *time.Time) error
+	// FindDeletedPaged = user yang sudah di-soft delete, terakhir dihapus dulu
+	FindDeletedPaged(ctx context.Context, page, pageSize int) ([]domain.User, int64, error)
+	// Restore mengembalikan user terhapus; false jika tidak ada user terhapus dengan id tsb
+	Restore(ctx context.Context, id uuid.UUID) (bool, error)
+	// PurgeDeleted menghapus permanen user yang di-soft delete sebelum waktu before
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
+// ErrVersionConflict = versi user sudah berubah sejak dibaca (optimistic concurrency)
+var ErrVersionConflict = errors.New("versi user sudah berubah")
+
 type userRepo struct{ db *gorm.DB }
 
 func NewUserRepository(db *gorm.DB) UserRepository {
@@ -41,21 +74,124 @@ func (r *userRepo) FindAll(ctx context.Context) ([]domain.User, error) {
 	return out, err
 }
 
-func (r *userRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	var u domain.User
-	err := r.db.WithContext(ctx).First(&u, "id = ?", id).Error
+func (r *userRepo) FindByID(ctx context.Context, id uuid.UUID, fields ...string) (*domain.User, error) {
+	cols, err := selectColumns(fields, nil)
 	if err != nil {
 		return nil, err
 	}
+	var u domain.User
+	db := r.db.WithContext(ctx)
+	if cols != nil {
+		// version selalu ikut untuk ETag
+		if !slices.Contains(cols, "version") {
+			cols = append(cols, "version")
+		}
+		db = db.Select(cols)
+	}
+	if err := db.First(&u, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
 	return &u, nil
 }
 
 func (r *userRepo) Update(ctx context.Context, u *domain.User) error {
-	return r.db.WithContext(ctx).Save(u).Error
+	now := time.Now()
+	res := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND version = ?", u.ID, u.Version).
+		Updates(map[string]any{
+			"name":       u.Name,
+			"email":      u.Email,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	u.Version++
+	u.UpdatedAt = now
+	return nil
+}
+
+func (r *userRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	db := r.db.WithContext(ctx).Where("id = ?", id)
+	if version > 0 {
+		db = db.Where("version = ?", version)
+	}
+	res := db.Delete(&domain.User{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		// dengan If-Match, user yang tidak ada juga dianggap versi tidak cocok (RFC 9110)
+		if version > 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status, reason string, until *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          status,
+			"status_reason":   reason,
+			"suspended_until": until,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *userRepo) FindDeletedPaged(ctx context.Context, page, pageSize int) ([]domain.User, int64, error) {
+	var (
+		items []domain.User
+		total int64
+	)
+	base := r.db.WithContext(ctx).Unscoped().Model(&domain.User{}).Where("deleted_at IS NOT NULL")
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []domain.User{}, 0, nil
+	}
+	err := base.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "deleted_at"}, Desc: true}).
+		Scopes(scopePaginate(page, pageSize)).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *userRepo) Restore(ctx context.Context, id uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&domain.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
 }
 
-func (r *userRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id).Error
+func (r *userRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&domain.User{})
+	return res.RowsAffected, res.Error
 }
 
 // =========================
@@ -65,46 +201,237 @@ func (r *userRepo) Delete(ctx context.Context, id uuid.UUID) error {
 const MaxPageSize = 100
 
 var allowedSort = map[string]struct{}{
-	"created_at": {},
-	"name":       {},
-	"email":      {},
+	"created_at":  {},
+	"name":        {},
+	"email":       {},
+	SortRelevance: {}, // bukan kolom, lihat relevanceExpr
+}
+
+// userFields = field yang boleh diminta lewat fields=... (nama JSON → kolom)
+var userFields = map[string]string{
+	"id":                "id",
+	"name":              "name",
+	"email":             "email",
+	"email_verified_at": "email_verified_at",
+	"created_at":        "created_at",
+	"updated_at":        "updated_at",
+	"status":            "status",
+	"suspended_until":   "suspended_until",
+	"version":           "version",
 }
 
+// ParseUserSort mem-parse sort=-created_at,name ("-" = menurun) terhadap allowedSort
+func ParseUserSort(spec string) ([]filter.SortKey, error) {
+	return filter.ParseSort(spec, allowedSort)
+}
+
+// ParseUserFields memvalidasi sparse fieldset (fields=id,name) terhadap userFields
+func ParseUserFields(names []string) ([]string, error) {
+	return filter.ParseFields(names, userFields)
+}
+
+// UserListOptions = urutan & kolom yang diambil untuk list user
+type UserListOptions struct {
+	Sort   []filter.SortKey // dari ParseUserSort; kosong = -created_at
+	Fields []string         // dari ParseUserFields; kosong = semua kolom
+}
+
+func (o UserListOptions) sort() ([]filter.SortKey, error) {
+	if len(o.Sort) == 0 {
+		return []filter.SortKey{{Field: "created_at", Desc: true}}, nil
+	}
+	for _, k := range o.Sort {
+		if _, ok := allowedSort[k.Field]; !ok {
+			return nil, &filter.Error{Key: "sort", Msg: "kolom tidak dikenal: " + k.Field}
+		}
+	}
+	return o.Sort, nil
+}
+
+// selectColumns = kolom untuk SELECT: fields + kolom sort (dibutuhkan cursor) + id; nil = semua
+func selectColumns(fields []string, sort []filter.SortKey) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	cols := []string{"id"}
+	for _, f := range fields {
+		col, ok := userFields[f]
+		if !ok {
+			return nil, &filter.Error{Key: "fields", Msg: "field tidak dikenal: " + f}
+		}
+		if !slices.Contains(cols, col) {
+			cols = append(cols, col)
+		}
+	}
+	for _, k := range sort {
+		if k.Field != SortRelevance && !slices.Contains(cols, k.Field) {
+			cols = append(cols, k.Field)
+		}
+	}
+	return cols, nil
+}
+
+// userFilters = field yang boleh dipakai di filter[field][op]=value; status dibandingkan
+// dengan status efektif, lihat userFilterSchema
+var userFilters = filter.Schema{
+	"id":                {Column: "id", Kind: filter.UUID},
+	"name":              {Column: "name"},
+	"email":             {Column: "email"},
+	"status":            {Column: "status", Enum: []string{domain.UserStatusActive, domain.UserStatusSuspended, domain.UserStatusDisabled}},
+	"created_at":        {Column: "created_at", Kind: filter.Time},
+	"updated_at":        {Column: "updated_at", Kind: filter.Time},
+	"email_verified_at": {Column: "email_verified_at", Kind: filter.Time, Nullable: true},
+	"suspended_until":   {Column: "suspended_until", Kind: filter.Time, Nullable: true},
+}
+
+// UserFilter = filter list user
+type UserFilter struct {
+	Q          string // dicari di nama / email sesuai SearchMode
+	SearchMode string // SearchContains ("" = default) atau SearchFuzzy
+	Status     string // status efektif (suspend yang sudah lewat dihitung active); "" = semua
+	Where      []filter.Condition
+}
+
+// userFilterSchema = userFilters dengan filter[status] memakai status efektif per now, sama dengan scopeStatus
+func userFilterSchema(now time.Time) filter.Schema {
+	s := maps.Clone(userFilters)
+	status := s["status"]
+	expr := effectiveStatus(now)
+	status.Expr = &expr
+	s["status"] = status
+	return s
+}
+
+func scopeUserFilter(f UserFilter) (func(*gorm.DB) *gorm.DB, error) {
+	now := time.Now()
+	where, err := userFilterSchema(now).Scope(f.Where)
+	if err != nil {
+		return nil, err
+	}
+	status := scopeStatus(f.Status, now)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(status, scopeSearch(f.Q, f.SearchMode), where)
+	}, nil
+}
+
+// Mode pencarian q (UserFilter.SearchMode)
+const (
+	SearchContains = "contains" // default: substring pada name / email
+	// SearchFuzzy: prefix kata (full-text) atau mirip (trigram, toleran typo); butuh migrasi
+	// 000001_users_search (pg_trgm)
+	SearchFuzzy = "fuzzy"
+)
+
+// SortRelevance mengurutkan berdasarkan kemiripan dengan q (butuh q, hanya mode offset)
+const SortRelevance = "relevance"
+
+// userSearchVector harus sama persis dengan ekspresi index idx_users_search_tsv
+const userSearchVector = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, ''))"
+
 // scopes
-func scopeSearch(q string) func(*gorm.DB) *gorm.DB {
+func scopeSearch(q, mode string) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if s := strings.TrimSpace(q); s != "" {
-			like := "%" + s + "%"
-			// Use database-agnostic case-insensitive search
-			// GORM will handle the appropriate SQL syntax for different databases
-			return db.Where("LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)", like, like)
+		s := strings.TrimSpace(q)
+		if s == "" {
+			return db
+		}
+		if mode == SearchFuzzy {
+			return db.Where(fuzzyMatch(s))
 		}
-		return db
+		like := "%" + s + "%"
+		// Use database-agnostic case-insensitive search
+		// GORM will handle the appropriate SQL syntax for different databases
+		return db.Where("LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)", like, like)
+	}
+}
+
+// fuzzyMatch: kata diawali q (full-text prefix) atau q mirip bagian dari name / email
+// (word similarity >= pg_trgm.word_similarity_threshold). Semua bentuk memakai index.
+func fuzzyMatch(q string) clause.Expr {
+	q = strings.ToLower(q)
+	sql := "lower(name) %> ? OR lower(email) %> ?"
+	vars := []any{q, q}
+	if tsq := prefixTSQuery(q); tsq != "" {
+		sql = userSearchVector + " @@ to_tsquery('simple', ?) OR " + sql
+		vars = append([]any{tsq}, vars...)
+	}
+	return clause.Expr{SQL: "(" + sql + ")", Vars: vars}
+}
+
+// relevanceExpr: skor full-text + kemiripan trigram tertinggi dari name / email
+func relevanceExpr(q string) (string, []any) {
+	q = strings.ToLower(strings.TrimSpace(q))
+	sql := "greatest(word_similarity(?, lower(name)), word_similarity(?, lower(email)))"
+	vars := []any{q, q}
+	if tsq := prefixTSQuery(q); tsq != "" {
+		sql = "ts_rank(" + userSearchVector + ", to_tsquery('simple', ?)) + " + sql
+		vars = append([]any{tsq}, vars...)
+	}
+	return sql, vars
+}
+
+// prefixTSQuery mengubah "bud san" menjadi "bud:* & san:*". Hanya huruf & angka yang
+// dipertahankan supaya input tidak bisa menyisipkan operator tsquery.
+func prefixTSQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// effectiveStatus = status efektif dalam SQL, konsisten dengan User.EffectiveStatus
+// (suspend yang sudah lewat dihitung active)
+func effectiveStatus(now time.Time) clause.Expr {
+	return clause.Expr{
+		SQL:  "CASE WHEN status = ? AND suspended_until <= ? THEN ? ELSE status END",
+		Vars: []any{domain.UserStatusSuspended, now, domain.UserStatusActive},
 	}
 }
 
-func scopeSort(sortBy, sortDir string) func(*gorm.DB) *gorm.DB {
+// scopeStatus memfilter berdasarkan status efektif, konsisten dengan User.EffectiveStatus
+func scopeStatus(status string, now time.Time) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		// Validate and sanitize sort column
-		if sortBy == "" {
-			sortBy = "created_at"
+		if status == "" {
+			return db
 		}
+		return db.Where(clause.Eq{Column: effectiveStatus(now), Value: status})
+	}
+}
 
-		// Use strict whitelist validation to prevent SQL injection
-		if _, ok := allowedSort[sortBy]; !ok {
-			sortBy = "created_at"
+// scopeSort mengurutkan sesuai sort (sudah divalidasi terhadap allowedSort) lalu id sebagai
+// penentu dengan arah kolom terakhir. flip membalik semua arah (keyset mundur).
+// Semua kolom dirakit dalam satu ORDER BY karena GORM tidak bisa menggabungkan
+// ekspresi (relevance) dengan kolom di beberapa pemanggilan Order.
+func scopeSort(q string, sort []filter.SortKey, flip bool) func(*gorm.DB) *gorm.DB {
+	var (
+		parts []string
+		vars  []any
+	)
+	dir := func(desc bool) string {
+		if desc != flip {
+			return " DESC"
 		}
-
-		// Validate sort direction
-		desc := true
-		if strings.EqualFold(sortDir, "asc") {
-			desc = false
+		return ""
+	}
+	for _, k := range sort {
+		if k.Field == SortRelevance {
+			if strings.TrimSpace(q) == "" {
+				continue
+			}
+			sql, v := relevanceExpr(q)
+			parts = append(parts, "("+sql+")"+dir(k.Desc))
+			vars = append(vars, v...)
+			continue
 		}
+		parts = append(parts, "?"+dir(k.Desc))
+		vars = append(vars, clause.Column{Name: k.Field})
+	}
+	parts = append(parts, "?"+dir(sort[len(sort)-1].Desc))
+	vars = append(vars, clause.Column{Name: "id"})
 
-		return db.Order(clause.OrderByColumn{
-			Column: clause.Column{Name: sortBy},
-			Desc:   desc,
-		})
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(parts, ", "), Vars: vars}})
 	}
 }
 
@@ -129,9 +456,9 @@ func scopePaginate(page, pageSize int) func(*gorm.DB) *gorm.DB {
 
 func (r *userRepo) FindPaged(
 	ctx context.Context,
-	q string,
+	f UserFilter,
+	o UserListOptions,
 	page, pageSize int,
-	sortBy, sortDir string,
 ) ([]domain.User, int64, error) {
 
 	var (
@@ -139,10 +466,23 @@ func (r *userRepo) FindPaged(
 		total int64
 	)
 
-	base := r.db.WithContext(ctx).Model(&domain.User{})
+	filtered, err := scopeUserFilter(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort, err := o.sort()
+	if err != nil {
+		return nil, 0, err
+	}
+	cols, err := selectColumns(o.Fields, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Session: statement baru untuk setiap query turunan (count & find)
+	base := r.db.WithContext(ctx).Model(&domain.User{}).Session(&gorm.Session{})
 
 	// hitung total
-	if err := base.Scopes(scopeSearch(q)).Count(&total).Error; err != nil {
+	if err := base.Scopes(filtered).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 	// optional: kalau offset di luar total, kembalikan kosong cepat
@@ -150,11 +490,13 @@ func (r *userRepo) FindPaged(
 		return []domain.User{}, 0, nil
 	}
 
-	// ambil data
-	if err := base.
-		// pilih kolom yang diperlukan saja agar hemat memori (opsional)
-		// Select("id", "name", "email", "phone", "created_at").
-		Scopes(scopeSearch(q), scopeSort(sortBy, sortDir), scopePaginate(page, pageSize)).
+	// ambil data; cols nil = semua kolom
+	find := base
+	if cols != nil {
+		find = find.Select(cols)
+	}
+	if err := find.
+		Scopes(filtered, scopeSort(f.Q, sort, false), scopePaginate(page, pageSize)).
 		Find(&items).Error; err != nil {
 		return nil, 0, err
 	}
@@ -179,3 +521,189 @@ func (r *userRepo) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash st
 			"updated_at":    gorm.Expr("now()"),
 		}).Error
 }
+
+func (r *userRepo) ReplacePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND password_hash = ?", id, oldHash).
+		UpdateColumn("password_hash", newHash).Error
+}
+
+func (r *userRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND email = ?", id, email).
+		Updates(map[string]any{
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, now())"),
+			// sudah terverifikasi = data tidak berubah, ETag client tetap berlaku
+			"version":    gorm.Expr("CASE WHEN email_verified_at IS NULL THEN version + 1 ELSE version END"),
+			"updated_at": gorm.Expr("CASE WHEN email_verified_at IS NULL THEN now() ELSE updated_at END"),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *userRepo) UpdateName(ctx context.Context, id uuid.UUID, name string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"name":       name,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": gorm.Expr("now()"),
+		}).Error
+}
+
+func (r *userRepo) ChangeEmail(ctx context.Context, id uuid.UUID, email string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email":             email,
+			"email_verified_at": gorm.Expr("now()"),
+			"version":           gorm.Expr("version + 1"),
+			"updated_at":        gorm.Expr("now()"),
+		}).Error
+}
+
+// UserKeyset = parameter keyset pagination. After nil = halaman pertama.
+type UserKeyset struct {
+	After *UserKeysetPos
+	// Backward mengambil baris sebelum After (untuk prev_cursor); hasil tetap dalam urutan sort
+	Backward bool
+	Limit    int
+}
+
+// UserKeysetPos = posisi baris acuan: nilai setiap kolom sort (lihat UserKeysetValues) dan id
+type UserKeysetPos struct {
+	Values []string
+	ID     uuid.UUID
+}
+
+// UserKeysetValues = nilai kolom sort u dalam bentuk string untuk disimpan di cursor
+func UserKeysetValues(u *domain.User, sort []filter.SortKey) []string {
+	out := make([]string, len(sort))
+	for i, k := range sort {
+		switch k.Field {
+		case "name":
+			out[i] = u.Name
+		case "email":
+			out[i] = u.Email
+		default:
+			out[i] = u.CreatedAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return out
+}
+
+func keysetArg(col, value string) (any, error) {
+	if col == "created_at" {
+		return time.Parse(time.RFC3339Nano, value)
+	}
+	return value, nil
+}
+
+// keysetAfter = baris setelah pos sesuai urutan sort (+ id). Jika semua arah sama cukup
+// perbandingan tuple (bisa pakai index); jika campuran diuraikan menjadi
+// c1 > v1 OR (c1 = v1 AND c2 < v2) OR ...
+func keysetAfter(sort []filter.SortKey, pos *UserKeysetPos, flip bool) (clause.Expr, error) {
+	if len(pos.Values) != len(sort) {
+		return clause.Expr{}, &filter.Error{Key: "cursor", Msg: "jumlah nilai cursor tidak sesuai sort"}
+	}
+	keys := append(slices.Clip(sort), filter.SortKey{Field: "id", Desc: sort[len(sort)-1].Desc})
+	vals := make([]any, 0, len(keys))
+	for i, k := range sort {
+		v, err := keysetArg(k.Field, pos.Values[i])
+		if err != nil {
+			return clause.Expr{}, &filter.Error{Key: "cursor", Msg: "nilai cursor tidak valid untuk " + k.Field}
+		}
+		vals = append(vals, v)
+	}
+	vals = append(vals, pos.ID)
+
+	op := func(k filter.SortKey) string {
+		if k.Desc != flip {
+			return "<"
+		}
+		return ">"
+	}
+	sameDir := !slices.ContainsFunc(keys, func(k filter.SortKey) bool { return k.Desc != keys[0].Desc })
+	if sameDir {
+		marks := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		vars := make([]any, 0, 2*len(keys))
+		for _, k := range keys {
+			vars = append(vars, clause.Column{Name: k.Field})
+		}
+		vars = append(vars, vals...)
+		return clause.Expr{SQL: "(" + marks + ") " + op(keys[0]) + " (" + marks + ")", Vars: vars}, nil
+	}
+
+	var (
+		ors  []string
+		vars []any
+	)
+	for i, k := range keys {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, "? = ?")
+			vars = append(vars, clause.Column{Name: keys[j].Field}, vals[j])
+		}
+		ands = append(ands, "? "+op(k)+" ?")
+		vars = append(vars, clause.Column{Name: k.Field}, vals[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(ors, " OR ") + ")", Vars: vars}, nil
+}
+
+func (r *userRepo) FindKeyset(ctx context.Context, f UserFilter, o UserListOptions, k UserKeyset) ([]domain.User, error) {
+	filtered, err := scopeUserFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := o.sort()
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(sort, func(k filter.SortKey) bool { return k.Field == SortRelevance }) {
+		return nil, &filter.Error{Key: "sort", Msg: "relevance tidak didukung di keyset pagination"}
+	}
+	cols, err := selectColumns(o.Fields, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	db := r.db.WithContext(ctx).Model(&domain.User{}).Scopes(filtered)
+	if cols != nil {
+		db = db.Select(cols)
+	}
+	if k.After != nil {
+		// mundur = ambil dengan urutan terbalik dari posisi acuan, lalu dibalik lagi
+		after, err := keysetAfter(sort, k.After, k.Backward)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(after)
+	}
+
+	var items []domain.User
+	err = db.Scopes(scopeSort(f.Q, sort, k.Backward)).Limit(k.Limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	if k.Backward {
+		slices.Reverse(items)
+	}
+	return items, nil
+}
+
+func (r *userRepo) CountFiltered(ctx context.Context, f UserFilter) (int64, error) {
+	filtered, err := scopeUserFilter(f)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = r.db.WithContext(ctx).Model(&domain.User{}).Scopes(filtered).Count(&total).Error
+	return total, err
+}
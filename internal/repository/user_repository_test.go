@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/filter"
+)
+
+func TestKeysetAfter(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	id := uuid.MustParse("0b5d1c1e-6f0a-4c59-9a43-5c1f1f0f0b11")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+
+	tests := []struct {
+		name     string
+		sort     []filter.SortKey
+		values   []string
+		flip     bool
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "satu kolom menurun",
+			sort:     []filter.SortKey{{Field: "created_at", Desc: true}},
+			values:   []string{created.Format(time.RFC3339Nano)},
+			wantSQL:  `("created_at", "id") < ($1, $2)`,
+			wantVars: []any{created, id},
+		},
+		{
+			name:     "arah sama dibalik",
+			sort:     []filter.SortKey{{Field: "name"}, {Field: "email"}},
+			values:   []string{"Budi", "budi@example.com"},
+			flip:     true,
+			wantSQL:  `("name", "email", "id") < ($1, $2, $3)`,
+			wantVars: []any{"Budi", "budi@example.com", id},
+		},
+		{
+			name:     "arah campuran",
+			sort:     []filter.SortKey{{Field: "created_at", Desc: true}, {Field: "name"}},
+			values:   []string{created.Format(time.RFC3339Nano), "Budi"},
+			wantSQL:  `(("created_at" < $1) OR ("created_at" = $2 AND "name" > $3) OR ("created_at" = $4 AND "name" = $5 AND "id" > $6))`,
+			wantVars: []any{created, created, "Budi", created, "Budi", id},
+		},
+		{
+			name:     "arah campuran dibalik",
+			sort:     []filter.SortKey{{Field: "name"}, {Field: "email", Desc: true}},
+			values:   []string{"Budi", "budi@example.com"},
+			flip:     true,
+			wantSQL:  `(("name" < $1) OR ("name" = $2 AND "email" > $3) OR ("name" = $4 AND "email" = $5 AND "id" > $6))`,
+			wantVars: []any{"Budi", "Budi", "budi@example.com", "Budi", "budi@example.com", id},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := keysetAfter(tt.sort, &UserKeysetPos{Values: tt.values, ID: id}, tt.flip)
+			if err != nil {
+				t.Fatalf("keysetAfter: %v", err)
+			}
+			stmt := &gorm.Statement{DB: db}
+			expr.Build(stmt)
+			if got := stmt.SQL.String(); got != tt.wantSQL {
+				t.Fatalf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			if len(stmt.Vars) != len(tt.wantVars) {
+				t.Fatalf("vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+			for i, v := range stmt.Vars {
+				if w, ok := tt.wantVars[i].(time.Time); ok {
+					if got, _ := v.(time.Time); !got.Equal(w) {
+						t.Fatalf("vars[%d] = %v, want %v", i, v, w)
+					}
+				} else if v != tt.wantVars[i] {
+					t.Fatalf("vars[%d] = %v, want %v", i, v, tt.wantVars[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeysetAfterRejectsBadCursor(t *testing.T) {
+	sort := []filter.SortKey{{Field: "created_at", Desc: true}, {Field: "name"}}
+	tests := []struct {
+		name   string
+		values []string
+	}{
+		{name: "jumlah nilai kurang", values: []string{"2024-01-02T03:04:05Z"}},
+		{name: "jumlah nilai lebih", values: []string{"2024-01-02T03:04:05Z", "Budi", "x"}},
+		{name: "waktu tidak valid", values: []string{"kemarin", "Budi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := keysetAfter(sort, &UserKeysetPos{Values: tt.values, ID: uuid.New()}, false)
+			var fe *filter.Error
+			if !errors.As(err, &fe) || fe.Key != "cursor" {
+				t.Fatalf("keysetAfter error = %v, want *filter.Error key cursor", err)
+			}
+		})
+	}
+}
+
+// filter[status] dan parameter status sama-sama memakai status efektif (suspend yang sudah lewat = active)
+func TestScopeUserFilterEffectiveStatus(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	const want = `CASE WHEN status = $1 AND suspended_until <= $2 THEN $3 ELSE status END`
+
+	tests := []struct {
+		name     string
+		f        UserFilter
+		wantSQL  string
+		wantLast any
+	}{
+		{name: "parameter status", f: UserFilter{Status: "suspended"}, wantSQL: want + ` = $4`, wantLast: "suspended"},
+		{name: "filter eq", f: UserFilter{Where: []filter.Condition{{Field: "status", Op: filter.Eq, Value: "suspended"}}}, wantSQL: want + ` = $4`, wantLast: "suspended"},
+		{name: "filter nin", f: UserFilter{Where: []filter.Condition{{Field: "status", Op: filter.NotIn, Value: "active,disabled"}}}, wantSQL: want + ` NOT IN ($4,$5)`, wantLast: "disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := scopeUserFilter(tt.f)
+			if err != nil {
+				t.Fatalf("scopeUserFilter: %v", err)
+			}
+			stmt := db.Scopes(scope).Find(&[]domain.User{}).Statement
+			if got := stmt.SQL.String(); !strings.Contains(got, tt.wantSQL) {
+				t.Fatalf("SQL = %s\nwant berisi %s", got, tt.wantSQL)
+			}
+			if last := stmt.Vars[len(stmt.Vars)-1]; last != tt.wantLast {
+				t.Fatalf("vars = %v, want nilai terakhir %v", stmt.Vars, tt.wantLast)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+)
+
+type APIKeyService interface {
+	// Create membuat key baru; key lengkap hanya dikembalikan sekali di sini
+	Create(ctx context.Context, userID uuid.UUID, in CreateAPIKeyInput) (*domain.APIKey, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, userID uuid.UUID, id string) error
+	// AuthenticateAPIKey dipakai middleware.AuthBearer; user dikembalikan lengkap dengan Roles
+	AuthenticateAPIKey(ctx context.Context, raw string) (*domain.APIKey, *domain.User, error)
+}
+
+type CreateAPIKeyInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+const (
+	apiKeyIDBytes     = 6  // bagian publik, jadi Prefix
+	apiKeySecretBytes = 32 // bagian rahasia
+	maxAPIKeysPerUser = 20
+)
+
+type apiKeySvc struct {
+	repo  repository.APIKeyRepository
+	users repository.UserRepository
+	roles repository.RoleRepository
+}
+
+func NewAPIKeySvc(repo repository.APIKeyRepository, users repository.UserRepository, roles repository.RoleRepository) APIKeyService {
+	return &apiKeySvc{repo: repo, users: users, roles: roles}
+}
+
+func (s *apiKeySvc) Create(ctx context.Context, userID uuid.UUID, in CreateAPIKeyInput) (*domain.APIKey, string, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > 100 {
+		return nil, "", apperr.Validation("name wajib diisi (maksimal 100 karakter)", nil)
+	}
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return nil, "", apperr.Validation("expires_at harus di masa depan", nil)
+	}
+
+	existing, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, "", apperr.Internal("gagal mengambil api key", err)
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, "", apperr.BadRequest("jumlah api key sudah mencapai batas, cabut key yang tidak dipakai", nil)
+	}
+
+	idPart := make([]byte, apiKeyIDBytes)
+	if _, err := rand.Read(idPart); err != nil {
+		return nil, "", apperr.Internal("gagal membuat api key", err)
+	}
+	secret, err := auth.NewOpaqueToken(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", apperr.Internal("gagal membuat api key", err)
+	}
+	prefix := domain.APIKeyPrefix + hex.EncodeToString(idPart)
+	raw := prefix + "_" + secret
+
+	k := &domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: in.ExpiresAt,
+	}
+	if err := s.repo.Create(ctx, k); err != nil {
+		return nil, "", apperr.Internal("gagal menyimpan api key", err)
+	}
+	return k, raw, nil
+}
+
+func (s *apiKeySvc) List(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	keys, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil api key", err)
+	}
+	return keys, nil
+}
+
+func (s *apiKeySvc) Revoke(ctx context.Context, userID uuid.UUID, id string) error {
+	kid, err := uuid.Parse(id)
+	if err != nil {
+		return apperr.BadRequest("id tidak valid", err)
+	}
+	ok, err := s.repo.Revoke(ctx, userID, kid)
+	if err != nil {
+		return apperr.Internal("gagal mencabut api key", err)
+	}
+	if !ok {
+		return apperr.NotFound("api key tidak ditemukan", nil)
+	}
+	return nil
+}
+
+func (s *apiKeySvc) AuthenticateAPIKey(ctx context.Context, raw string) (*domain.APIKey, *domain.User, error) {
+	raw = strings.TrimSpace(raw)
+	invalid := apperr.New("api_key_invalid", 401, "api key tidak valid", nil)
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) {
+		return nil, nil, invalid
+	}
+
+	k, err := s.repo.FindActiveByHash(ctx, auth.HashToken(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, invalid
+		}
+		return nil, nil, apperr.Internal("gagal mengecek api key", err)
+	}
+	u, err := s.users.FindByID(ctx, k.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, invalid
+		}
+		return nil, nil, apperr.Internal("gagal mengambil user", err)
+	}
+	if err := accountStatusError(u); err != nil {
+		return nil, nil, err
+	}
+	roles, err := s.roles.RolesForUser(ctx, u.ID)
+	if err != nil {
+		return nil, nil, apperr.Internal("gagal mengambil role", err)
+	}
+	u.Roles = roleNames(roles)
+
+	// gagal mencatat last_used tidak boleh menggagalkan request
+	if err := s.repo.TouchLastUsed(ctx, k.ID); err != nil {
+		log.Printf("api key %s: gagal update last_used_at: %v", k.Prefix, err)
+	}
+	return k, u, nil
+}
+
+// normalizeScopes memvalidasi scope terhadap domain.KnownPermissions ("*" dan "users:*" juga boleh)
+func normalizeScopes(in []string) ([]string, error) {
+	out := []string{}
+	for _, sc := range in {
+		sc = strings.ToLower(strings.TrimSpace(sc))
+		if sc == "" || slices.Contains(out, sc) {
+			continue
+		}
+		known := false
+		for _, p := range domain.KnownPermissions {
+			if domain.PermissionMatches(sc, p) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, apperr.Validation("scope tidak dikenal: "+sc, nil)
+		}
+		out = append(out, sc)
+	}
+	return out, nil
+}
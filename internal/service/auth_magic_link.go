@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/mailer"
+)
+
+// RequestMagicLink mengirim link login sekali pakai ke email jika terdaftar. Seperti
+// ForgotPassword, response selalu sama agar tidak membocorkan email mana yang terdaftar.
+// Link lama yang belum dipakai otomatis tidak berlaku lagi.
+func (s *authSvc) RequestMagicLink(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.v.Var(email, "required,email"); err != nil {
+		return apperr.Validation("email tidak valid", err)
+	}
+
+	u, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("magic link: find user: %v", err)
+		}
+		return nil
+	}
+
+	if err := s.actions.InvalidateForUser(ctx, u.ID, domain.TokenPurposeMagicLink); err != nil {
+		return apperr.Internal("gagal menonaktifkan link lama", err)
+	}
+	raw, err := s.issueActionToken(ctx, u.ID, domain.TokenPurposeMagicLink, s.cfg.MagicLinkTTL, u.Email)
+	if err != nil {
+		return err
+	}
+	// link ke frontend (bukan langsung ke API) supaya link scanner di email client tidak
+	// memakai token sebelum user mengkliknya
+	link := s.cfg.AppBaseURL + "/magic-link?token=" + url.QueryEscape(raw)
+	msg := mailer.Message{
+		To:      u.Email,
+		Subject: "Link login",
+		Body: "Halo " + u.Name + ",\n\n" +
+			"Gunakan link berikut untuk login tanpa password (berlaku " + s.cfg.MagicLinkTTL.String() + ", sekali pakai):\n" +
+			link + "\n\n" +
+			"Abaikan email ini jika Anda tidak meminta link login.\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("magic link: send mail: %v", err)
+	}
+	return nil
+}
+
+// MagicLinkLogin menukar token dari link login dengan token akses. Karena link hanya bisa
+// dibuka dari inbox user, email sekaligus dianggap terverifikasi. 2FA tetap diminta jika aktif.
+func (s *authSvc) MagicLinkLogin(ctx context.Context, token string) (*LoginResult, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, apperr.Validation("token wajib diisi", nil)
+	}
+	at, err := s.consumeActionToken(ctx, domain.TokenPurposeMagicLink, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// akun suspended / disabled tidak boleh login, jadi email juga tidak ditandai terverifikasi
+	u, err := s.findUser(ctx, at.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := accountStatusError(u); err != nil {
+		return nil, err
+	}
+
+	// token tidak berlaku jika email user sudah berubah sejak link dikirim
+	ok, err := s.repo.MarkEmailVerified(ctx, at.UserID, at.Target)
+	if err != nil {
+		return nil, apperr.Internal("gagal menyimpan verifikasi email", err)
+	}
+	if !ok {
+		return nil, apperr.BadRequest("token tidak valid atau sudah kedaluwarsa", nil)
+	}
+	if u, err = s.findUser(ctx, at.UserID); err != nil {
+		return nil, err
+	}
+
+	// login lewat email membuktikan pemilik akun, hitungan gagal login password di-reset
+	if err := s.guard.Succeed(ctx, u.Email); err != nil {
+		return nil, err
+	}
+	return s.completeLogin(ctx, u)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/oidc"
+)
+
+// OAuthStateTTL = umur state antara /start dan /callback
+const OAuthStateTTL = 10 * time.Minute
+
+// OAuthStart menyiapkan state + PKCE dan mengembalikan URL authorization provider beserta state-nya
+// (handler mengikat state ke browser lewat cookie).
+func (s *authSvc) OAuthStart(ctx context.Context, provider string) (authURL, state string, err error) {
+	p, err := s.oauthProvider(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err = auth.NewOpaqueToken(32)
+	if err != nil {
+		return "", "", apperr.Internal("gagal membuat state", err)
+	}
+	nonce, err := auth.NewOpaqueToken(32)
+	if err != nil {
+		return "", "", apperr.Internal("gagal membuat nonce", err)
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", "", apperr.Internal("gagal membuat PKCE", err)
+	}
+
+	authURL, err = p.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		return "", "", apperr.New("oauth_provider_error", 502, "gagal menghubungi provider", err)
+	}
+	if err := s.identities.SaveState(ctx, &domain.OAuthState{
+		StateHash:    auth.HashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(OAuthStateTTL),
+	}); err != nil {
+		return "", "", apperr.Internal("gagal menyimpan state", err)
+	}
+	return authURL, state, nil
+}
+
+// OAuthCallback menukar authorization code, memverifikasi id_token, lalu mencari/menautkan/membuat user.
+// Akun yang sudah ada hanya ditautkan otomatis jika provider menyatakan email terverifikasi.
+func (s *authSvc) OAuthCallback(ctx context.Context, provider, code, state string) (*LoginResult, error) {
+	p, err := s.oauthProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(code) == "" || strings.TrimSpace(state) == "" {
+		return nil, apperr.BadRequest("code dan state wajib diisi", nil)
+	}
+
+	st, err := s.identities.ConsumeState(ctx, auth.HashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.BadRequest("state tidak valid atau kedaluwarsa", err)
+		}
+		return nil, apperr.Internal("gagal mengambil state", err)
+	}
+	if st.Provider != provider {
+		return nil, apperr.BadRequest("state tidak valid atau kedaluwarsa", nil)
+	}
+
+	tok, err := p.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		return nil, apperr.Unauthorized("gagal menukar authorization code", err)
+	}
+	claims, err := p.VerifyIDToken(ctx, tok.IDToken, st.Nonce)
+	if err != nil {
+		return nil, apperr.Unauthorized("id_token tidak valid", err)
+	}
+
+	u, err := s.resolveOAuthUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.completeLogin(ctx, u)
+}
+
+func (s *authSvc) resolveOAuthUser(ctx context.Context, provider string, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	// 1) identity sudah tertaut
+	ident, err := s.identities.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		u, err := s.repo.FindByID(ctx, ident.UserID)
+		if err != nil {
+			return nil, apperr.Internal("gagal mengambil user", err)
+		}
+		return u, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.Internal("gagal mengambil identity", err)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if err := s.v.Var(email, "required,email"); err != nil {
+		return nil, apperr.BadRequest("provider tidak mengirim email yang valid", err)
+	}
+	verified := bool(claims.EmailVerified)
+
+	// 2) user dengan email sama → tautkan hanya jika email terverifikasi di provider
+	u, err := s.repo.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if !verified {
+			return nil, apperr.Conflict("email sudah terdaftar; login dengan password untuk menautkan akun", nil)
+		}
+		if u.EmailVerifiedAt == nil {
+			// provider sudah membuktikan kepemilikan email
+			if _, err := s.repo.MarkEmailVerified(ctx, u.ID, u.Email); err != nil {
+				return nil, apperr.Internal("gagal menyimpan verifikasi email", err)
+			}
+			now := time.Now()
+			u.EmailVerifiedAt = &now
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 3) user baru tanpa password
+		name := strings.TrimSpace(claims.Name)
+		if name == "" {
+			name = strings.Split(email, "@")[0]
+		}
+		u = &domain.User{Name: name, Email: email}
+		if verified {
+			now := time.Now()
+			u.EmailVerifiedAt = &now
+		}
+		if err := s.repo.Create(ctx, u); err != nil {
+			if ae := apperr.FromPg(err); ae != nil {
+				// email didaftarkan request lain setelah FindByEmail di atas
+				if ae.Code == "duplicate" {
+					return nil, apperr.Conflict("email baru saja terdaftar, silakan login ulang", err)
+				}
+				return nil, ae
+			}
+			return nil, apperr.Internal("gagal menyimpan user", err)
+		}
+		if err := s.grantDefaultRole(ctx, u); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+
+	if err := s.identities.Create(ctx, &domain.UserIdentity{
+		UserID:   u.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+	}); err != nil {
+		// callback lain untuk akun provider yang sama sudah menautkannya lebih dulu
+		if ae := apperr.FromPg(err); ae != nil && ae.Code == "duplicate" {
+			return nil, apperr.Conflict("akun "+provider+" sudah ditautkan, silakan login ulang", err)
+		}
+		return nil, apperr.Internal("gagal menyimpan identity", err)
+	}
+	return u, nil
+}
+
+func (s *authSvc) oauthProvider(name string) (*oidc.Provider, error) {
+	p, ok := s.oauth[name]
+	if !ok {
+		return nil, apperr.NotFound("provider tidak dikenal", nil)
+	}
+	return p, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/oidc"
+)
+
+func TestResolveOAuthUserDuplicate(t *testing.T) {
+	verifiedAt := time.Now()
+	existing := &domain.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com", EmailVerifiedAt: &verifiedAt}
+	tests := []struct {
+		name        string
+		users       *fakeUserRepo
+		identities  *fakeIdentityRepo
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "email didaftarkan bersamaan",
+			users:       &fakeUserRepo{err: errUniqueViolation},
+			identities:  &fakeIdentityRepo{},
+			wantStatus:  409,
+			wantMessage: "email baru saja terdaftar, silakan login ulang",
+		},
+		{
+			name:        "identity ditautkan bersamaan",
+			users:       &fakeUserRepo{user: existing},
+			identities:  &fakeIdentityRepo{err: errUniqueViolation},
+			wantStatus:  409,
+			wantMessage: "akun google sudah ditautkan, silakan login ulang",
+		},
+		{
+			name:        "gagal simpan user",
+			users:       &fakeUserRepo{err: errors.New("connection reset")},
+			identities:  &fakeIdentityRepo{},
+			wantStatus:  500,
+			wantMessage: "gagal menyimpan user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &authSvc{repo: tt.users, identities: tt.identities, v: validator.New()}
+			claims := &oidc.IDTokenClaims{Email: "budi@example.com", EmailVerified: true, RegisteredClaims: jwt.RegisteredClaims{Subject: "1234"}}
+			_, err := s.resolveOAuthUser(context.Background(), "google", claims)
+			var ae *apperr.AppError
+			if !errors.As(err, &ae) {
+				t.Fatalf("error = %v, want *apperr.AppError", err)
+			}
+			if ae.HTTPStatus != tt.wantStatus || ae.Message != tt.wantMessage {
+				t.Fatalf("error = %d %q, want %d %q", ae.HTTPStatus, ae.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/clientinfo"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/mailer"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/password"
+)
+
+// ProfileUpdate: field nil = tidak diubah
+type ProfileUpdate struct {
+	Name  *string
+	Email *string
+}
+
+// ProfileResult: PendingEmail terisi jika ganti email menunggu verifikasi alamat baru
+type ProfileResult struct {
+	User         *domain.User
+	PendingEmail string
+}
+
+func (s *authSvc) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	newPassword = strings.TrimSpace(newPassword)
+	if currentPassword == "" {
+		return apperr.Validation("current_password wajib diisi", nil)
+	}
+	if newPassword == "" {
+		return apperr.Validation("new_password wajib diisi", nil)
+	}
+
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.PasswordHash == nil || *u.PasswordHash == "" {
+		return apperr.BadRequest("akun belum memiliki password, gunakan fitur lupa password untuk membuat password", nil)
+	}
+
+	// password lama salah dihitung seperti login gagal, supaya token curian tidak bisa dipakai menebak password
+	ip := clientinfo.From(ctx).IP
+	if err := s.guard.Check(ctx, u.Email, ip); err != nil {
+		return err
+	}
+	if _, err := s.hasher.Verify(currentPassword, *u.PasswordHash); err != nil {
+		return s.loginFailed(ctx, u.Email, ip, apperr.Unauthorized("password saat ini salah", err))
+	}
+	if err := s.guard.Succeed(ctx, u.Email); err != nil {
+		return err
+	}
+	if currentPassword == newPassword {
+		return apperr.Validation("password baru harus berbeda dari password saat ini", nil)
+	}
+	if err := s.checkPassword(newPassword, u.Email, u.Name); err != nil {
+		return err
+	}
+
+	hash, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, u.ID, hash); err != nil {
+		return apperr.Internal("gagal menyimpan password", err)
+	}
+	if err := s.actions.InvalidateForUser(ctx, u.ID, domain.TokenPurposePasswordReset); err != nil {
+		return apperr.Internal("gagal menonaktifkan token reset", err)
+	}
+
+	// session lain (perangkat lain) dicabut, session yang sedang dipakai tetap login
+	if err := s.refresh.RevokeOthersForUser(ctx, u.ID, sessionID); err != nil {
+		return apperr.Internal("gagal mencabut refresh token", err)
+	}
+	if err := s.sessions.RevokeOthers(ctx, u.ID, sessionID); err != nil {
+		return apperr.Internal("gagal mencabut session", err)
+	}
+	return nil
+}
+
+func (s *authSvc) UpdateProfile(ctx context.Context, userID uuid.UUID, in ProfileUpdate) (*ProfileResult, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if err := s.v.Var(name, "required,min=2"); err != nil {
+			return nil, apperr.Validation("nama minimal 2 karakter", err)
+		}
+		if name != u.Name {
+			if err := s.repo.UpdateName(ctx, u.ID, name); err != nil {
+				return nil, apperr.Internal("gagal menyimpan data", err)
+			}
+			u.Name = name
+		}
+	}
+
+	res := &ProfileResult{User: u}
+	if in.Email == nil {
+		return res, nil
+	}
+	email := strings.ToLower(strings.TrimSpace(*in.Email))
+	if email == u.Email {
+		return res, nil
+	}
+	if err := s.v.Var(email, "required,email"); err != nil {
+		return nil, apperr.Validation("email tidak valid", err)
+	}
+	if _, err := s.repo.FindByEmail(ctx, email); err == nil {
+		return nil, apperr.Conflict("email sudah terdaftar, gunakan email lain", nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.Internal("gagal mengecek email", err)
+	}
+
+	// email baru baru dipasang setelah link di email baru diklik (lihat VerifyEmail)
+	if err := s.actions.InvalidateForUser(ctx, u.ID, domain.TokenPurposeEmailChange); err != nil {
+		return nil, apperr.Internal("gagal menonaktifkan token ganti email", err)
+	}
+	raw, err := s.issueActionToken(ctx, u.ID, domain.TokenPurposeEmailChange, s.cfg.EmailVerifyTTL, email)
+	if err != nil {
+		return nil, err
+	}
+	s.sendMail(ctx, "email change", mailer.Message{
+		To:      email,
+		Subject: "Konfirmasi email baru",
+		Body: "Halo " + u.Name + ",\n\n" +
+			"Konfirmasi alamat email baru Anda melalui link berikut (berlaku " + s.cfg.EmailVerifyTTL.String() + "):\n" +
+			s.cfg.AppBaseURL + "/verify-email?token=" + url.QueryEscape(raw) + "\n",
+	})
+	s.sendMail(ctx, "email change", mailer.Message{
+		To:      u.Email,
+		Subject: "Permintaan ganti email",
+		Body: "Halo " + u.Name + ",\n\n" +
+			"Ada permintaan mengganti email akun Anda ke " + email + ".\n" +
+			"Jika ini bukan Anda, segera ganti password Anda.\n",
+	})
+	res.PendingEmail = email
+	return res, nil
+}
+
+// applyEmailChange memasang email baru dari token email_change. Access token lama dicabut
+// karena claim email-nya sudah basi; client cukup refresh.
+func (s *authSvc) applyEmailChange(ctx context.Context, at *domain.ActionToken) error {
+	if err := s.repo.ChangeEmail(ctx, at.UserID, at.Target); err != nil {
+		if ae := apperr.FromPg(err); ae != nil && ae.Code == "duplicate" {
+			return apperr.Conflict("email sudah dipakai akun lain", err)
+		}
+		return apperr.Internal("gagal menyimpan email", err)
+	}
+	if err := s.actions.InvalidateForUser(ctx, at.UserID, domain.TokenPurposeEmailVerify); err != nil {
+		return apperr.Internal("gagal menonaktifkan token verifikasi", err)
+	}
+	if err := s.revocations.RevokeUser(ctx, at.UserID, time.Now()); err != nil {
+		return apperr.Internal("gagal mencabut token", err)
+	}
+	return nil
+}
+
+// checkPassword memvalidasi password baru terhadap PasswordPolicy
+func (s *authSvc) checkPassword(pw, email, name string) error {
+	return checkPasswordPolicy(s.passwords, pw, email, name)
+}
+
+func checkPasswordPolicy(policy *password.Policy, pw, email, name string) error {
+	err := policy.Validate(pw, password.UserInfo{Email: email, Name: name})
+	var ve *password.ViolationError
+	if errors.As(err, &ve) {
+		return apperr.Validation(ve.Error(), err)
+	}
+	if err != nil {
+		return apperr.Internal("gagal mengecek password", err)
+	}
+	return nil
+}
+
+// hashPassword membuat hash dengan Hasher yang dikonfigurasi (argon2id / bcrypt)
+func (s *authSvc) hashPassword(pw string) (string, error) {
+	hash, err := s.hasher.Hash(pw)
+	if err != nil {
+		return "", apperr.Internal("gagal hash password", err)
+	}
+	return hash, nil
+}
+
+func (s *authSvc) findUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("user tidak ditemukan", err)
+		}
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+	return u, nil
+}
+
+// sendMail: gagal kirim hanya di-log, tidak menggagalkan request
+func (s *authSvc) sendMail(ctx context.Context, kind string, msg mailer.Message) {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("%s: send mail: %v", kind, err)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+)
+
+func TestApplyEmailChangeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		// email diklaim akun lain di antara permintaan ganti email dan konfirmasi
+		{name: "email sudah dipakai", err: errUniqueViolation, wantStatus: 409, wantMsg: "email sudah dipakai akun lain"},
+		{name: "error lain", err: errors.New("connection reset"), wantStatus: 500, wantMsg: "gagal menyimpan email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &authSvc{repo: &fakeUserRepo{err: tt.err}}
+			err := s.applyEmailChange(context.Background(), &domain.ActionToken{UserID: uuid.New(), Target: "baru@example.com"})
+			var ae *apperr.AppError
+			if !errors.As(err, &ae) {
+				t.Fatalf("error = %v, want *apperr.AppError", err)
+			}
+			if ae.HTTPStatus != tt.wantStatus || ae.Message != tt.wantMsg {
+				t.Fatalf("error = %d %q, want %d %q", ae.HTTPStatus, ae.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
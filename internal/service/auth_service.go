@@ -3,134 +3,793 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
 	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
 	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/clientinfo"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/mailer"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/oidc"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/password"
 )
 
 type AuthService interface {
-	Register(ctx context.Context, name, email, password string) (*domain.User, string, error)
-	Login(ctx context.Context, email, password string) (*domain.User, string, error)
+	Register(ctx context.Context, name, email, password string) (*domain.User, *TokenPair, error)
+	Login(ctx context.Context, email, password string) (*LoginResult, error)
+	OAuthStart(ctx context.Context, provider string) (authURL, state string, err error)
+	OAuthCallback(ctx context.Context, provider, code, state string) (*LoginResult, error)
+	// VerifyMFA menukar challenge dari Login + kode 2FA dengan token
+	VerifyMFA(ctx context.Context, mfaToken, code string) (*domain.User, *TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.User, *TokenPair, error)
+	// Logout mencabut access token (jti), session-nya, dan family refresh token jika diberikan
+	Logout(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time, sessionID uuid.UUID, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	ForgotPassword(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// RequestMagicLink mengirim link login tanpa password; MagicLinkLogin menukarnya dengan token
+	RequestMagicLink(ctx context.Context, email string) error
+	MagicLinkLogin(ctx context.Context, token string) (*LoginResult, error)
 	AdminSetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error
+	// ChangePassword (self-service) mewajibkan password lama dan mencabut session selain sessionID
+	ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error
+	// UpdateProfile mengubah nama langsung; email baru menunggu verifikasi lewat VerifyEmail
+	UpdateProfile(ctx context.Context, userID uuid.UUID, in ProfileUpdate) (*ProfileResult, error)
+	// UnlockAccount membuka kunci login akun yang terkena lockout
+	UnlockAccount(ctx context.Context, userID uuid.UUID) error
+	// SetStatus (admin) men-suspend / disable / mengaktifkan lagi akun; selain active semua sesi dicabut
+	SetStatus(ctx context.Context, actorID uuid.UUID, userID string, ch StatusChange) (*domain.User, error)
+	// AccountActive dipakai middleware.AuthBearer agar token user yang di-suspend langsung ditolak
+	AccountActive(ctx context.Context, userID uuid.UUID) error
 }
 
+// TokenPair = access token (JWT) + refresh token (opaque, disimpan hash-nya di DB)
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // detik, umur access token
+}
+
+// LoginResult: Tokens terisi jika login selesai, MFA terisi jika user masih harus verifikasi 2FA
+type LoginResult struct {
+	User   *domain.User
+	Tokens *TokenPair
+	MFA    *MFAChallenge
+}
+
+type MFAChallenge struct {
+	Token     string
+	ExpiresIn int64 // detik
+}
+
+// AuthDeps = repository/store yang dibutuhkan authSvc
+type AuthDeps struct {
+	Users         repository.UserRepository
+	RefreshTokens repository.RefreshTokenRepository
+	Revocations   repository.RevocationStore
+	Roles         repository.RoleRepository
+	ActionTokens  repository.ActionTokenRepository
+	Mailer        mailer.Mailer
+	MFA           MFAService
+	Identities    repository.IdentityRepository
+	Sessions      repository.SessionRepository
+	Passwords     *password.Policy
+	Hasher        password.Hasher
+	LoginGuard    LoginGuard
+	// provider OIDC per nama (mis. "google"); boleh kosong
+	OAuth map[string]*oidc.Provider
+}
+
+// AuthConfig = pengaturan token
+type AuthConfig struct {
+	JWTKeys    *auth.KeySet
+	JWTOptions auth.TokenOptions
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+
+	AppBaseURL       string // untuk link di email
+	PasswordResetTTL time.Duration
+
+	EmailVerifyTTL     time.Duration
+	VerificationPolicy string // lihat VerifyPolicy*
+
+	MFAChallengeTTL time.Duration
+
+	MagicLinkTTL time.Duration
+}
+
+// Kebijakan untuk user yang emailnya belum terverifikasi
+const (
+	VerifyPolicyOff      = "off"      // tidak dibatasi
+	VerifyPolicyLogin    = "login"    // tidak bisa login sampai verifikasi
+	VerifyPolicyRestrict = "restrict" // bisa login, tapi route tertentu ditolak (lihat middleware.RequireVerifiedEmail)
+)
+
+// panjang entropi refresh token & token email (byte)
+const (
+	refreshTokenBytes = 32
+	actionTokenBytes  = 32
+)
+
+// batas salah kode 2FA per challenge
+const mfaMaxAttempts = 5
+
 type authSvc struct {
-	repo      repository.UserRepository
-	v         *validator.Validate
-	jwtSecret string
-	accessTTL time.Duration
+	repo        repository.UserRepository
+	refresh     repository.RefreshTokenRepository
+	revocations repository.RevocationStore
+	roles       repository.RoleRepository
+	actions     repository.ActionTokenRepository
+	mailer      mailer.Mailer
+	mfa         MFAService
+	identities  repository.IdentityRepository
+	sessions    repository.SessionRepository
+	passwords   *password.Policy
+	hasher      password.Hasher
+	guard       LoginGuard
+	oauth       map[string]*oidc.Provider
+	v           *validator.Validate
+	cfg         AuthConfig
 }
 
-func NewAuthSvc(r repository.UserRepository, v *validator.Validate, jwtSecret string, accessTTL time.Duration) AuthService {
+func NewAuthSvc(d AuthDeps, v *validator.Validate, cfg AuthConfig) AuthService {
 	if v == nil {
 		v = validator.New()
 	}
-	return &authSvc{repo: r, v: v, jwtSecret: jwtSecret, accessTTL: accessTTL}
+	return &authSvc{
+		repo:        d.Users,
+		refresh:     d.RefreshTokens,
+		revocations: d.Revocations,
+		roles:       d.Roles,
+		actions:     d.ActionTokens,
+		mailer:      d.Mailer,
+		mfa:         d.MFA,
+		identities:  d.Identities,
+		sessions:    d.Sessions,
+		passwords:   d.Passwords,
+		hasher:      d.Hasher,
+		guard:       d.LoginGuard,
+		oauth:       d.OAuth,
+		v:           v,
+		cfg:         cfg,
+	}
 }
 
 type regDTO struct {
 	Name     string `validate:"required,min=2"`
 	Email    string `validate:"required,email"`
-	Password string `validate:"required,min=6"`
+	Password string `validate:"required"` // aturan lain lewat PasswordPolicy
 }
 
-func (s *authSvc) Register(ctx context.Context, name, email, password string) (*domain.User, string, error) {
+func (s *authSvc) Register(ctx context.Context, name, email, password string) (*domain.User, *TokenPair, error) {
 	in := regDTO{
 		Name:     strings.TrimSpace(name),
 		Email:    strings.ToLower(strings.TrimSpace(email)),
 		Password: strings.TrimSpace(password),
 	}
 	if err := s.v.Struct(in); err != nil {
-		return nil, "", apperr.Validation(err.Error(), err)
+		return nil, nil, apperr.Validation(err.Error(), err)
+	}
+	if err := s.checkPassword(in.Password, in.Email, in.Name); err != nil {
+		return nil, nil, err
 	}
 
 	// cek email existing
 	if _, err := s.repo.FindByEmail(ctx, in.Email); err == nil {
-		return nil, "", apperr.Conflict("email sudah terdaftar", nil)
+		return nil, nil, apperr.Conflict("email sudah terdaftar", nil)
 	}
 
 	// hash
-	hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+	hash, err := s.hashPassword(in.Password)
 	if err != nil {
-		return nil, "", apperr.Internal("gagal hash password", err)
+		return nil, nil, err
 	}
-	hs := string(hash)
 
-	u := &domain.User{Name: in.Name, Email: in.Email, PasswordHash: &hs}
+	u := &domain.User{Name: in.Name, Email: in.Email, PasswordHash: &hash}
 	if err := s.repo.Create(ctx, u); err != nil {
-		return nil, "", apperr.Internal("gagal menyimpan user", err)
+		return nil, nil, apperr.Internal("gagal menyimpan user", err)
+	}
+
+	if err := s.grantDefaultRole(ctx, u); err != nil {
+		return nil, nil, err
 	}
 
-	tok, _, err := auth.NewAccessToken(s.jwtSecret, u.ID, u.Email, s.accessTTL)
+	if err := s.sendVerificationEmail(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	// policy "login": token baru diberikan setelah email diverifikasi
+	if s.cfg.VerificationPolicy == VerifyPolicyLogin {
+		return u, nil, nil
+	}
+
+	tp, err := s.issueTokens(ctx, u)
 	if err != nil {
-		return nil, "", apperr.Internal("gagal membuat token", err)
+		return nil, nil, err
 	}
-	return u, tok, nil
+	return u, tp, nil
 }
 
 // internal/service/auth_service.go (potongan)
-func (s *authSvc) Login(ctx context.Context, email, password string) (*domain.User, string, error) {
+func (s *authSvc) Login(ctx context.Context, email, password string) (*LoginResult, error) {
 	email = strings.ToLower(strings.TrimSpace(email))
 	password = strings.TrimSpace(password)
 
 	if err := s.v.Var(email, "required,email"); err != nil {
-		return nil, "", apperr.Validation("email tidak valid", err)
+		return nil, apperr.Validation("email tidak valid", err)
 	}
 	if password == "" {
-		return nil, "", apperr.Validation("password wajib diisi", nil)
+		return nil, apperr.Validation("password wajib diisi", nil)
+	}
+
+	ip := clientinfo.From(ctx).IP
+	if err := s.guard.Check(ctx, email, ip); err != nil {
+		return nil, err
 	}
 
 	u, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, "", apperr.Unauthorized("email atau password salah", err)
+		return nil, s.loginFailed(ctx, email, ip, apperr.Unauthorized("email atau password salah", err))
 	}
 
 	// ⬇️ Tambahan: jika user belum punya password
 	if u.PasswordHash == nil || *u.PasswordHash == "" {
-		return nil, "", apperr.Unauthorized("akun belum memiliki password, minta admin untuk set password terlebih dahulu", nil)
+		return nil, s.loginFailed(ctx, email, ip, apperr.Unauthorized("akun belum memiliki password, login lewat link email (magic link) atau gunakan fitur lupa password untuk membuat password", nil))
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(*u.PasswordHash), []byte(password)); err != nil {
-		return nil, "", apperr.Unauthorized("email atau password salah", err)
+	needsRehash, err := s.hasher.Verify(password, *u.PasswordHash)
+	if err != nil {
+		return nil, s.loginFailed(ctx, email, ip, apperr.Unauthorized("email atau password salah", err))
+	}
+	if needsRehash {
+		s.rehashPassword(ctx, u, password)
 	}
 
-	tok, _, err := auth.NewAccessToken(s.jwtSecret, u.ID, u.Email, s.accessTTL)
+	if err := s.guard.Succeed(ctx, email); err != nil {
+		return nil, err
+	}
+
+	if s.cfg.VerificationPolicy == VerifyPolicyLogin && u.EmailVerifiedAt == nil {
+		return nil, apperr.EmailNotVerified("email belum diverifikasi, cek inbox atau kirim ulang email verifikasi", nil)
+	}
+
+	return s.completeLogin(ctx, u)
+}
+
+// rehashPassword menyimpan ulang hash dengan algoritma / parameter terbaru setelah login berhasil.
+// Gagal di sini tidak menggagalkan login; hash lama tetap valid dan dicoba lagi di login berikutnya.
+func (s *authSvc) rehashPassword(ctx context.Context, u *domain.User, pw string) {
+	hash, err := s.hasher.Hash(pw)
 	if err != nil {
-		return nil, "", apperr.Internal("gagal membuat token", err)
+		log.Printf("rehash password user %s: %v", u.ID, err)
+		return
+	}
+	// hanya diganti jika hash belum berubah sejak dibaca (mis. reset password bersamaan)
+	if err := s.repo.ReplacePasswordHash(ctx, u.ID, *u.PasswordHash, hash); err != nil {
+		log.Printf("rehash password user %s: %v", u.ID, err)
+		return
 	}
-	return u, tok, nil
+	u.PasswordHash = &hash
 }
 
-func (s *authSvc) AdminSetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
+// loginFailed mencatat kegagalan ke LoginGuard; error lockout menggantikan cause jika akun baru saja dikunci
+func (s *authSvc) loginFailed(ctx context.Context, email, ip string, cause error) error {
+	if err := s.guard.Fail(ctx, email, ip); err != nil {
+		return err
+	}
+	return cause
+}
+
+// completeLogin menerbitkan token, atau challenge 2FA jika user mengaktifkan MFA.
+func (s *authSvc) completeLogin(ctx context.Context, u *domain.User) (*LoginResult, error) {
+	if err := accountStatusError(u); err != nil {
+		return nil, err
+	}
+	enabled, err := s.mfa.Enabled(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		raw, err := s.issueActionToken(ctx, u.ID, domain.TokenPurposeMFAChallenge, s.cfg.MFAChallengeTTL, "")
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: u, MFA: &MFAChallenge{Token: raw, ExpiresIn: int64(s.cfg.MFAChallengeTTL.Seconds())}}, nil
+	}
+
+	tp, err := s.issueTokens(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: u, Tokens: tp}, nil
+}
+
+// VerifyMFA memeriksa kode 2FA terhadap challenge dari Login. Challenge hangus setelah
+// dipakai sukses atau setelah mfaMaxAttempts kali salah.
+func (s *authSvc) VerifyMFA(ctx context.Context, mfaToken, code string) (*domain.User, *TokenPair, error) {
+	mfaToken = strings.TrimSpace(mfaToken)
+	if mfaToken == "" {
+		return nil, nil, apperr.Validation("mfa_token wajib diisi", nil)
+	}
+	at, err := s.actions.FindActive(ctx, domain.TokenPurposeMFAChallenge, auth.HashToken(mfaToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperr.Unauthorized("challenge 2FA tidak valid atau kedaluwarsa, silakan login ulang", err)
+		}
+		return nil, nil, apperr.Internal("gagal mengambil challenge", err)
+	}
+
+	if err := s.mfa.Verify(ctx, at.UserID, code); err != nil {
+		var ae *apperr.AppError
+		if errors.As(err, &ae) && ae.HTTPStatus == 401 {
+			if err := s.actions.RecordFailedAttempt(ctx, at.ID, mfaMaxAttempts); err != nil {
+				return nil, nil, apperr.Internal("gagal menyimpan percobaan", err)
+			}
+		}
+		return nil, nil, err
+	}
+
+	// challenge sekali pakai
+	ok, err := s.actions.Consume(ctx, at.ID)
+	if err != nil {
+		return nil, nil, apperr.Internal("gagal memakai challenge", err)
+	}
+	if !ok {
+		return nil, nil, apperr.Unauthorized("challenge 2FA tidak valid atau kedaluwarsa, silakan login ulang", nil)
+	}
+
+	u, err := s.repo.FindByID(ctx, at.UserID)
+	if err != nil {
+		return nil, nil, apperr.Internal("gagal mengambil user", err)
+	}
+	if err := accountStatusError(u); err != nil {
+		return nil, nil, err
+	}
+	tp, err := s.issueTokens(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, tp, nil
+}
+
+// Refresh merotasi refresh token: token lama direvoke dan diganti token baru dalam family yang sama.
+// Jika token yang sudah dirotasi dipakai lagi (reuse), seluruh family dicabut.
+func (s *authSvc) Refresh(ctx context.Context, refreshToken string) (*domain.User, *TokenPair, error) {
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return nil, nil, apperr.Validation("refresh_token wajib diisi", nil)
+	}
+
+	rt, err := s.refresh.FindByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperr.Unauthorized("refresh token tidak valid", err)
+		}
+		return nil, nil, apperr.Internal("gagal mengambil refresh token", err)
+	}
+
+	// reuse detection: token lama dipakai lagi → cabut semua token turunan
+	if rt.RevokedAt != nil {
+		if err := s.revokeSession(ctx, rt.UserID, rt.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, apperr.Unauthorized("refresh token sudah dipakai, silakan login ulang", nil)
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		return nil, nil, apperr.Unauthorized("refresh token kedaluwarsa", nil)
+	}
+
+	u, err := s.repo.FindByID(ctx, rt.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperr.Unauthorized("refresh token tidak valid", err)
+		}
+		return nil, nil, apperr.Internal("gagal mengambil user", err)
+	}
+	if err := accountStatusError(u); err != nil {
+		return nil, nil, err
+	}
+
+	access, err := s.newAccessToken(ctx, u, rt.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	next, raw, err := s.newRefreshToken(u.ID, rt.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ok, err := s.refresh.Rotate(ctx, rt.ID, next)
+	if err != nil {
+		return nil, nil, apperr.Internal("gagal menyimpan refresh token", err)
+	}
+	if !ok {
+		// kalah balapan dengan request lain yang memakai token yang sama → anggap reuse
+		if err := s.revokeSession(ctx, rt.UserID, rt.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, apperr.Unauthorized("refresh token sudah dipakai, silakan login ulang", nil)
+	}
+
+	now := time.Now()
+	client := clientinfo.From(ctx)
+	if err := s.sessions.Extend(ctx, &domain.Session{
+		ID:         rt.FamilyID,
+		UserID:     u.ID,
+		UserAgent:  clientinfo.Truncate(client.UserAgent, 512),
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  next.ExpiresAt,
+	}); err != nil {
+		return nil, nil, apperr.Internal("gagal memperbarui session", err)
+	}
+
+	return u, &TokenPair{AccessToken: access, RefreshToken: raw, ExpiresIn: int64(s.cfg.AccessTTL.Seconds())}, nil
+}
+
+// Logout mencabut access token yang sedang dipakai (by jti) dan, jika dikirim, refresh token-nya.
+func (s *authSvc) Logout(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time, sessionID uuid.UUID, refreshToken string) error {
+	if err := s.revocations.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return apperr.Internal("gagal mencabut token", err)
+	}
+	if sessionID != uuid.Nil {
+		if err := s.revokeSession(ctx, userID, sessionID); err != nil {
+			return err
+		}
+	}
+
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return nil
+	}
+	rt, err := s.refresh.FindByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // token tidak dikenal, tidak ada yang perlu dicabut
+		}
+		return apperr.Internal("gagal mengambil refresh token", err)
+	}
+	if rt.UserID != userID {
+		return apperr.Forbidden("refresh token bukan milik user ini", nil)
+	}
+	return s.revokeSession(ctx, userID, rt.FamilyID)
+}
+
+// LogoutAll mencabut seluruh access token (via not_before) dan refresh token milik user.
+func (s *authSvc) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.revocations.RevokeUser(ctx, userID, time.Now()); err != nil {
+		return apperr.Internal("gagal mencabut token", err)
+	}
+	if err := s.refresh.RevokeAllForUser(ctx, userID); err != nil {
+		return apperr.Internal("gagal mencabut refresh token", err)
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return apperr.Internal("gagal mencabut session", err)
+	}
+	return nil
+}
+
+// ForgotPassword mengirim link reset password ke email jika terdaftar.
+// Selalu sukses (kecuali input tidak valid) agar tidak membocorkan email mana yang terdaftar.
+func (s *authSvc) ForgotPassword(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.v.Var(email, "required,email"); err != nil {
+		return apperr.Validation("email tidak valid", err)
+	}
+
+	u, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("forgot password: find user: %v", err)
+		}
+		return nil
+	}
+
+	raw, err := s.issueActionToken(ctx, u.ID, domain.TokenPurposePasswordReset, s.cfg.PasswordResetTTL, "")
+	if err != nil {
+		return err
+	}
+	link := s.cfg.AppBaseURL + "/reset-password?token=" + url.QueryEscape(raw)
+	msg := mailer.Message{
+		To:      u.Email,
+		Subject: "Reset password",
+		Body: "Halo " + u.Name + ",\n\n" +
+			"Gunakan link berikut untuk mengatur ulang password Anda (berlaku " + s.cfg.PasswordResetTTL.String() + "):\n" +
+			link + "\n\n" +
+			"Abaikan email ini jika Anda tidak meminta reset password.\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("forgot password: send mail: %v", err)
+	}
+	return nil
+}
+
+// ResetPassword menukar token reset (sekali pakai) dengan password baru.
+// Setelah sukses semua token reset lain dan semua sesi user dicabut.
+func (s *authSvc) ResetPassword(ctx context.Context, token, newPassword string) error {
+	token = strings.TrimSpace(token)
 	newPassword = strings.TrimSpace(newPassword)
-	if err := s.v.Var(newPassword, "required,min=6"); err != nil {
-		return apperr.Validation("password minimal 6 karakter", err)
+	if token == "" {
+		return apperr.Validation("token wajib diisi", nil)
+	}
+	if newPassword == "" {
+		return apperr.Validation("password wajib diisi", nil)
 	}
 
-	// pastikan user ada
-	if _, err := s.repo.FindByID(ctx, userID); err != nil {
+	// cek policy sebelum token dipakai, supaya password lemah tidak menghanguskan link reset
+	at, err := s.actions.FindActive(ctx, domain.TokenPurposePasswordReset, auth.HashToken(token))
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperr.NotFound("user tidak ditemukan", err)
+			return apperr.BadRequest("token tidak valid atau sudah kedaluwarsa", nil)
 		}
-		return apperr.Internal("gagal mengambil user", err)
+		return apperr.Internal("gagal mengambil token", err)
+	}
+	u, err := s.findUser(ctx, at.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPassword(newPassword, u.Email, u.Name); err != nil {
+		return err
+	}
+	if _, err := s.consumeActionToken(ctx, domain.TokenPurposePasswordReset, token); err != nil {
+		return err
+	}
+
+	hash, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, at.UserID, hash); err != nil {
+		return apperr.Internal("gagal menyimpan password", err)
+	}
+	if err := s.actions.InvalidateForUser(ctx, at.UserID, domain.TokenPurposePasswordReset); err != nil {
+		return apperr.Internal("gagal menonaktifkan token reset", err)
+	}
+	return s.LogoutAll(ctx, at.UserID)
+}
+
+// VerifyEmail menandai email user terverifikasi menggunakan token dari email.
+func (s *authSvc) VerifyEmail(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return apperr.Validation("token wajib diisi", nil)
+	}
+	at, err := s.consumeActionToken(ctx, domain.TokenPurposeEmailVerify, token)
+	if err != nil {
+		// link konfirmasi ganti email (PATCH /users/me) memakai endpoint yang sama
+		change, changeErr := s.consumeActionToken(ctx, domain.TokenPurposeEmailChange, token)
+		if changeErr != nil {
+			return err
+		}
+		return s.applyEmailChange(ctx, change)
+	}
+	ok, err := s.repo.MarkEmailVerified(ctx, at.UserID, at.Target)
+	if err != nil {
+		return apperr.Internal("gagal menyimpan verifikasi email", err)
+	}
+	if !ok {
+		// email user sudah berubah sejak token dikirim
+		return apperr.BadRequest("token tidak valid atau sudah kedaluwarsa", nil)
+	}
+	return nil
+}
+
+// ResendVerification mengirim ulang email verifikasi. Seperti ForgotPassword,
+// response selalu sama agar tidak membocorkan email yang terdaftar.
+func (s *authSvc) ResendVerification(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.v.Var(email, "required,email"); err != nil {
+		return apperr.Validation("email tidak valid", err)
+	}
+	u, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("resend verification: find user: %v", err)
+		}
+		return nil
+	}
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.sendVerificationEmail(ctx, u)
+}
+
+// sendVerificationEmail membatalkan token verifikasi lama lalu mengirim token baru ke u.Email.
+func (s *authSvc) sendVerificationEmail(ctx context.Context, u *domain.User) error {
+	if err := s.actions.InvalidateForUser(ctx, u.ID, domain.TokenPurposeEmailVerify); err != nil {
+		return apperr.Internal("gagal menonaktifkan token verifikasi", err)
+	}
+	raw, err := s.issueActionToken(ctx, u.ID, domain.TokenPurposeEmailVerify, s.cfg.EmailVerifyTTL, u.Email)
+	if err != nil {
+		return err
+	}
+	link := s.cfg.AppBaseURL + "/verify-email?token=" + url.QueryEscape(raw)
+	msg := mailer.Message{
+		To:      u.Email,
+		Subject: "Verifikasi email",
+		Body: "Halo " + u.Name + ",\n\n" +
+			"Konfirmasi alamat email Anda melalui link berikut (berlaku " + s.cfg.EmailVerifyTTL.String() + "):\n" +
+			link + "\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("verification: send mail: %v", err)
+	}
+	return nil
+}
+
+// issueActionToken menyimpan hash token sekali pakai dan mengembalikan token mentah untuk email.
+// target opsional, mis. alamat email yang sedang diverifikasi.
+func (s *authSvc) issueActionToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration, target string) (string, error) {
+	raw, err := auth.NewOpaqueToken(actionTokenBytes)
+	if err != nil {
+		return "", apperr.Internal("gagal membuat token", err)
+	}
+	t := &domain.ActionToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: auth.HashToken(raw),
+		Target:    target,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.actions.Create(ctx, t); err != nil {
+		return "", apperr.Internal("gagal menyimpan token", err)
+	}
+	return raw, nil
+}
+
+// consumeActionToken memvalidasi lalu menandai token terpakai (atomik).
+func (s *authSvc) consumeActionToken(ctx context.Context, purpose, raw string) (*domain.ActionToken, error) {
+	invalid := apperr.BadRequest("token tidak valid atau sudah kedaluwarsa", nil)
+
+	at, err := s.actions.FindActive(ctx, purpose, auth.HashToken(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, apperr.Internal("gagal mengambil token", err)
+	}
+	ok, err := s.actions.Consume(ctx, at.ID)
+	if err != nil {
+		return nil, apperr.Internal("gagal memakai token", err)
+	}
+	if !ok {
+		return nil, invalid
+	}
+	return at, nil
+}
+
+// issueTokens membuat session baru (IP & user agent dari clientinfo) beserta access token +
+// refresh token-nya. Family refresh token = ID session.
+func (s *authSvc) issueTokens(ctx context.Context, u *domain.User) (*TokenPair, error) {
+	sessionID := uuid.New()
+	access, err := s.newAccessToken(ctx, u, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	rt, raw, err := s.newRefreshToken(u.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := clientinfo.From(ctx)
+	sess := &domain.Session{
+		ID:         sessionID,
+		UserID:     u.ID,
+		UserAgent:  clientinfo.Truncate(client.UserAgent, 512),
+		IP:         client.IP,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  rt.ExpiresAt,
+	}
+	if err := s.sessions.Create(ctx, sess); err != nil {
+		return nil, apperr.Internal("gagal menyimpan session", err)
+	}
+	if err := s.refresh.Create(ctx, rt); err != nil {
+		return nil, apperr.Internal("gagal menyimpan refresh token", err)
+	}
+	return &TokenPair{AccessToken: access, RefreshToken: raw, ExpiresIn: int64(s.cfg.AccessTTL.Seconds())}, nil
+}
+
+// revokeSession mencabut session beserta semua refresh token di family-nya
+func (s *authSvc) revokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.refresh.RevokeFamily(ctx, sessionID); err != nil {
+		return apperr.Internal("gagal mencabut refresh token", err)
+	}
+	if _, err := s.sessions.Revoke(ctx, userID, sessionID); err != nil {
+		return apperr.Internal("gagal mencabut session", err)
+	}
+	return nil
+}
+
+// grantDefaultRole memberi role "user" ke akun baru (register / OAuth)
+func (s *authSvc) grantDefaultRole(ctx context.Context, u *domain.User) error {
+	role, err := s.roles.FindByName(ctx, domain.RoleUser)
+	if err != nil {
+		return apperr.Internal("gagal mengambil role default", err)
+	}
+	if err := s.roles.Grant(ctx, u.ID, role.ID); err != nil {
+		return apperr.Internal("gagal menambahkan role default", err)
+	}
+	return nil
+}
+
+// newAccessToken memuat role user (untuk claim "roles") lalu menandatangani JWT.
+// u.Roles ikut diisi agar response login menampilkan role.
+func (s *authSvc) newAccessToken(ctx context.Context, u *domain.User, sessionID uuid.UUID) (string, error) {
+	roles, err := s.roles.RolesForUser(ctx, u.ID)
+	if err != nil {
+		return "", apperr.Internal("gagal mengambil role user", err)
+	}
+	u.Roles = roleNames(roles)
+
+	tok, _, err := auth.NewAccessToken(s.cfg.JWTKeys, s.cfg.JWTOptions, auth.Subject{
+		UserID:        u.ID,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		Roles:         u.Roles,
+		SessionID:     sessionID,
+	}, s.cfg.AccessTTL)
+	if err != nil {
+		return "", apperr.Internal("gagal membuat token", err)
+	}
+	return tok, nil
+}
+
+// newRefreshToken mengembalikan record (berisi hash) dan token mentah untuk dikirim ke client.
+func (s *authSvc) newRefreshToken(userID, familyID uuid.UUID) (*domain.RefreshToken, string, error) {
+	raw, err := auth.NewOpaqueToken(refreshTokenBytes)
+	if err != nil {
+		return nil, "", apperr.Internal("gagal membuat refresh token", err)
+	}
+	return &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(raw),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	}, raw, nil
+}
+
+func (s *authSvc) AdminSetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
+	newPassword = strings.TrimSpace(newPassword)
+	if newPassword == "" {
+		return apperr.Validation("password wajib diisi", nil)
+	}
+
+	// pastikan user ada
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPassword(newPassword, u.Email, u.Name); err != nil {
+		return err
 	}
 
 	// hash baru
-	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hash, err := s.hashPassword(newPassword)
 	if err != nil {
-		return apperr.Internal("gagal hash password", err)
+		return err
 	}
-	if err := s.repo.UpdatePasswordHash(ctx, userID, string(hash)); err != nil {
+	if err := s.repo.UpdatePasswordHash(ctx, userID, hash); err != nil {
 		return apperr.Internal("gagal menyimpan password", err)
 	}
 	return nil
 }
+
+func (s *authSvc) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.NotFound("user tidak ditemukan", err)
+		}
+		return apperr.Internal("gagal mengambil user", err)
+	}
+	return s.guard.Unlock(ctx, u.Email)
+}
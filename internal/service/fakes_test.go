@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+)
+
+// errUniqueViolation = error yang dikembalikan gorm.io/driver/postgres saat unique index dilanggar
+var errUniqueViolation = &pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "idx_users_email"`}
+
+// fakeUserRepo hanya mengimplementasikan method yang dipakai test; method lain panic
+type fakeUserRepo struct {
+	repository.UserRepository
+	user *domain.User
+	err  error // dikembalikan Create / Update / Restore / ChangeEmail
+}
+
+func (f *fakeUserRepo) FindByID(_ context.Context, id uuid.UUID, _ ...string) (*domain.User, error) {
+	u := *f.user
+	u.ID = id
+	return &u, nil
+}
+
+// FindByEmail: user nil = email belum terdaftar
+func (f *fakeUserRepo) FindByEmail(context.Context, string) (*domain.User, error) {
+	if f.user == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	u := *f.user
+	return &u, nil
+}
+
+func (f *fakeUserRepo) Create(context.Context, *domain.User) error { return f.err }
+
+func (f *fakeUserRepo) Update(context.Context, *domain.User) error { return f.err }
+
+func (f *fakeUserRepo) Restore(context.Context, uuid.UUID) (bool, error) { return f.err == nil, f.err }
+
+func (f *fakeUserRepo) ChangeEmail(context.Context, uuid.UUID, string) error { return f.err }
+
+type fakeInvitationRepo struct {
+	repository.InvitationRepository
+	inv *domain.Invitation
+}
+
+func (f *fakeInvitationRepo) FindUsableByHash(context.Context, string) (*domain.Invitation, error) {
+	inv := *f.inv
+	return &inv, nil
+}
+
+func (f *fakeInvitationRepo) MarkAccepted(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+// fakeTransactor menjalankan fn langsung dengan repository yang sama (tanpa transaksi)
+type fakeTransactor struct{ repos repository.TxRepos }
+
+func (f fakeTransactor) InTx(_ context.Context, fn func(r repository.TxRepos) error) error {
+	return fn(f.repos)
+}
+
+// fakeIdentityRepo: belum ada identity tertaut; Create mengembalikan err
+type fakeIdentityRepo struct {
+	repository.IdentityRepository
+	err error
+}
+
+func (f *fakeIdentityRepo) FindByProviderSubject(context.Context, string, string) (*domain.UserIdentity, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeIdentityRepo) Create(context.Context, *domain.UserIdentity) error { return f.err }
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/clientinfo"
+)
+
+type ImpersonationService interface {
+	// Impersonate menerbitkan access token singkat atas nama targetID dengan claim "act" = actorID.
+	// Tidak ada refresh token; setelah kedaluwarsa admin harus memulai ulang.
+	Impersonate(ctx context.Context, actorID uuid.UUID, targetID, reason string) (*ImpersonationResult, error)
+	// CheckActor memastikan admin pada token impersonation (claim "act") masih aktif dan masih
+	// punya permission users:impersonate. Dipanggil di setiap request, bukan hanya saat token terbit.
+	CheckActor(ctx context.Context, actorID uuid.UUID) error
+}
+
+type ImpersonationResult struct {
+	User        *domain.User
+	AccessToken string
+	ExpiresIn   int64 // detik
+}
+
+type ImpersonationConfig struct {
+	JWTKeys    *auth.KeySet
+	JWTOptions auth.TokenOptions
+	TTL        time.Duration
+}
+
+type impersonationSvc struct {
+	users repository.UserRepository
+	roles repository.RoleRepository
+	audit repository.AuditLogRepository
+	cfg   ImpersonationConfig
+}
+
+func NewImpersonationSvc(users repository.UserRepository, roles repository.RoleRepository, audit repository.AuditLogRepository, cfg ImpersonationConfig) ImpersonationService {
+	return &impersonationSvc{users: users, roles: roles, audit: audit, cfg: cfg}
+}
+
+func (s *impersonationSvc) Impersonate(ctx context.Context, actorID uuid.UUID, targetID, reason string) (*ImpersonationResult, error) {
+	uid, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil, apperr.BadRequest("user id tidak valid", err)
+	}
+	if uid == actorID {
+		return nil, apperr.BadRequest("tidak bisa impersonate diri sendiri", nil)
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		return nil, apperr.Validation("reason maksimal 500 karakter", nil)
+	}
+
+	u, err := s.users.FindByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("user tidak ditemukan", err)
+		}
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+
+	roles, err := s.roles.RolesForUser(ctx, u.ID)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil role user", err)
+	}
+	u.Roles = roleNames(roles)
+	// sesama admin tidak boleh saling impersonate (mencegah eskalasi / menyamarkan jejak)
+	privileged, err := rolesGrant(ctx, s.roles, u.Roles, domain.PermUsersImpersonate)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil permission user", err)
+	}
+	if privileged {
+		return nil, apperr.Forbidden("user ini tidak bisa di-impersonate", nil)
+	}
+
+	tok, claims, err := auth.NewAccessToken(s.cfg.JWTKeys, s.cfg.JWTOptions, auth.Subject{
+		UserID:        u.ID,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		Roles:         u.Roles,
+		ActorID:       actorID,
+	}, s.cfg.TTL)
+	if err != nil {
+		return nil, apperr.Internal("gagal membuat token", err)
+	}
+
+	ci := clientinfo.From(ctx)
+	if err := s.audit.Create(ctx, &domain.AuditLog{
+		Action:    domain.AuditImpersonationStart,
+		ActorID:   actorID,
+		UserID:    u.ID,
+		TokenID:   claims.ID,
+		IP:        ci.IP,
+		UserAgent: clientinfo.Truncate(ci.UserAgent, 512),
+		Detail:    reason,
+	}); err != nil {
+		// token tidak diberikan jika jejaknya gagal dicatat
+		return nil, apperr.Internal("gagal mencatat audit log", err)
+	}
+
+	return &ImpersonationResult{User: u, AccessToken: tok, ExpiresIn: int64(s.cfg.TTL.Seconds())}, nil
+}
+
+func (s *impersonationSvc) CheckActor(ctx context.Context, actorID uuid.UUID) error {
+	a, err := s.users.FindByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errImpersonationRevoked(err)
+		}
+		return apperr.Internal("gagal mengambil admin", err)
+	}
+	if err := accountStatusError(a); err != nil {
+		return errImpersonationRevoked(err)
+	}
+	roles, err := s.roles.RolesForUser(ctx, actorID)
+	if err != nil {
+		return apperr.Internal("gagal mengambil role admin", err)
+	}
+	allowed, err := rolesGrant(ctx, s.roles, roleNames(roles), domain.PermUsersImpersonate)
+	if err != nil {
+		return apperr.Internal("gagal mengambil permission admin", err)
+	}
+	if !allowed {
+		return errImpersonationRevoked(nil)
+	}
+	return nil
+}
+
+// errImpersonationRevoked: admin di-suspend / dinonaktifkan / dihapus atau kehilangan
+// permission users:impersonate setelah token impersonation terbit
+func errImpersonationRevoked(err error) error {
+	return apperr.New("impersonation_revoked", 401, "admin tidak lagi berhak impersonate, token tidak berlaku", err)
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/mailer"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/password"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/validation"
+)
+
+type InvitationService interface {
+	// Invite membuat undangan dan mengirim link-nya ke email. Memberi role tambahan
+	// mewajibkan inviter punya permission roles:write.
+	Invite(ctx context.Context, inviterID uuid.UUID, in InviteInput) (*domain.Invitation, error)
+	ListPending(ctx context.Context) ([]domain.Invitation, error)
+	// Resend menerbitkan token baru (token lama tidak berlaku) dan memperpanjang masa berlaku
+	Resend(ctx context.Context, id string) (*domain.Invitation, error)
+	Revoke(ctx context.Context, id string) error
+	// Accept membuat akun dari undangan dengan nama & password pilihan user
+	Accept(ctx context.Context, token, name, newPassword string) (*domain.User, error)
+}
+
+type InviteInput struct {
+	Email string
+	Name  string
+	Role  string // opsional
+}
+
+type InvitationDeps struct {
+	Invitations repository.InvitationRepository
+	Users       repository.UserRepository
+	Roles       repository.RoleRepository
+	Tx          repository.Transactor // Accept: user, role & status undangan dalam satu transaksi
+	Mailer      mailer.Mailer
+	Passwords   *password.Policy
+	Hasher      password.Hasher
+}
+
+type InvitationConfig struct {
+	AppBaseURL string // untuk link di email
+	TTL        time.Duration
+}
+
+type invitationSvc struct {
+	invitations repository.InvitationRepository
+	users       repository.UserRepository
+	roles       repository.RoleRepository
+	tx          repository.Transactor
+	mailer      mailer.Mailer
+	passwords   *password.Policy
+	hasher      password.Hasher
+	v           *validator.Validate
+	cfg         InvitationConfig
+}
+
+func NewInvitationSvc(d InvitationDeps, v *validator.Validate, cfg InvitationConfig) InvitationService {
+	if v == nil {
+		v = validator.New()
+	}
+	return &invitationSvc{
+		invitations: d.Invitations,
+		users:       d.Users,
+		roles:       d.Roles,
+		tx:          d.Tx,
+		mailer:      d.Mailer,
+		passwords:   d.Passwords,
+		hasher:      d.Hasher,
+		v:           v,
+		cfg:         cfg,
+	}
+}
+
+type inviteDTO struct {
+	Email string `validate:"required,email"`
+	Name  string `validate:"omitempty,min=2,max=120"`
+}
+
+func (s *invitationSvc) Invite(ctx context.Context, inviterID uuid.UUID, in InviteInput) (*domain.Invitation, error) {
+	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
+	in.Name = strings.TrimSpace(in.Name)
+	in.Role = strings.TrimSpace(in.Role)
+	if err := s.v.Struct(inviteDTO{Email: in.Email, Name: in.Name}); err != nil {
+		return nil, apperr.Validation(validation.FormatValidationError(err), err)
+	}
+
+	if _, err := s.users.FindByEmail(ctx, in.Email); err == nil {
+		return nil, apperr.Conflict("email sudah terdaftar", nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+	if _, err := s.invitations.FindPendingByEmail(ctx, in.Email); err == nil {
+		return nil, apperr.Conflict("undangan untuk email ini masih aktif, gunakan kirim ulang", nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.Internal("gagal mengambil undangan", err)
+	}
+
+	if in.Role != "" {
+		if err := s.checkRoleGrant(ctx, inviterID, in.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := auth.NewOpaqueToken(actionTokenBytes)
+	if err != nil {
+		return nil, apperr.Internal("gagal membuat token", err)
+	}
+	inv := &domain.Invitation{
+		Email:     in.Email,
+		Name:      in.Name,
+		Role:      in.Role,
+		InvitedBy: inviterID,
+		TokenHash: auth.HashToken(raw),
+		ExpiresAt: time.Now().Add(s.cfg.TTL),
+		SentAt:    time.Now(),
+	}
+	if err := s.invitations.Create(ctx, inv); err != nil {
+		return nil, apperr.Internal("gagal menyimpan undangan", err)
+	}
+	s.sendInvitation(ctx, inv, raw)
+	return inv, nil
+}
+
+// checkRoleGrant: role harus ada dan inviter harus boleh memberi role (roles:write)
+func (s *invitationSvc) checkRoleGrant(ctx context.Context, inviterID uuid.UUID, role string) error {
+	if _, err := s.roles.FindByName(ctx, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.Validation("role tidak ditemukan", err)
+		}
+		return apperr.Internal("gagal mengambil role", err)
+	}
+	inviterRoles, err := s.roles.RolesForUser(ctx, inviterID)
+	if err != nil {
+		return apperr.Internal("gagal mengambil role user", err)
+	}
+	ok, err := rolesGrant(ctx, s.roles, roleNames(inviterRoles), domain.PermRolesWrite)
+	if err != nil {
+		return apperr.Internal("gagal mengambil permission user", err)
+	}
+	if !ok {
+		return apperr.Forbidden("butuh permission roles:write untuk mengundang dengan role", nil)
+	}
+	return nil
+}
+
+func (s *invitationSvc) ListPending(ctx context.Context) ([]domain.Invitation, error) {
+	out, err := s.invitations.ListPending(ctx)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil undangan", err)
+	}
+	return out, nil
+}
+
+func (s *invitationSvc) Resend(ctx context.Context, id string) (*domain.Invitation, error) {
+	inv, err := s.findPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := auth.NewOpaqueToken(actionTokenBytes)
+	if err != nil {
+		return nil, apperr.Internal("gagal membuat token", err)
+	}
+	expiresAt := time.Now().Add(s.cfg.TTL)
+	ok, err := s.invitations.Reissue(ctx, inv.ID, auth.HashToken(raw), expiresAt)
+	if err != nil {
+		return nil, apperr.Internal("gagal menyimpan undangan", err)
+	}
+	if !ok {
+		return nil, apperr.NotFound("undangan tidak ditemukan", nil)
+	}
+	inv.ExpiresAt, inv.SentAt = expiresAt, time.Now()
+	s.sendInvitation(ctx, inv, raw)
+	return inv, nil
+}
+
+func (s *invitationSvc) Revoke(ctx context.Context, id string) error {
+	inv, err := s.findPending(ctx, id)
+	if err != nil {
+		return err
+	}
+	ok, err := s.invitations.Revoke(ctx, inv.ID)
+	if err != nil {
+		return apperr.Internal("gagal mencabut undangan", err)
+	}
+	if !ok {
+		return apperr.NotFound("undangan tidak ditemukan", nil)
+	}
+	return nil
+}
+
+type acceptInvitationDTO struct {
+	Name string `validate:"required,min=2,max=120"`
+}
+
+func (s *invitationSvc) Accept(ctx context.Context, token, name, newPassword string) (*domain.User, error) {
+	token = strings.TrimSpace(token)
+	name = strings.TrimSpace(name)
+	newPassword = strings.TrimSpace(newPassword)
+	if token == "" {
+		return nil, apperr.Validation("token wajib diisi", nil)
+	}
+
+	invalid := apperr.BadRequest("undangan tidak valid atau sudah kedaluwarsa", nil)
+	inv, err := s.invitations.FindUsableByHash(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, apperr.Internal("gagal mengambil undangan", err)
+	}
+
+	if name == "" {
+		name = inv.Name
+	}
+	if err := s.v.Struct(acceptInvitationDTO{Name: name}); err != nil {
+		return nil, apperr.Validation(validation.FormatValidationError(err), err)
+	}
+	if newPassword == "" {
+		return nil, apperr.Validation("password wajib diisi", nil)
+	}
+	if err := checkPasswordPolicy(s.passwords, newPassword, inv.Email, name); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.FindByEmail(ctx, inv.Email); err == nil {
+		return nil, apperr.Conflict("email sudah terdaftar, silakan login", nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return nil, apperr.Internal("gagal hash password", err)
+	}
+
+	// link dikirim ke email tsb, jadi email langsung dianggap terverifikasi
+	now := time.Now()
+	u := &domain.User{ID: uuid.New(), Name: name, Email: inv.Email, PasswordHash: &hash, EmailVerifiedAt: &now}
+	roles := []string{domain.RoleUser}
+	if inv.Role != "" && inv.Role != domain.RoleUser {
+		roles = append(roles, inv.Role)
+	}
+
+	// user, role dan status undangan disimpan bersama: gagal di tengah = undangan tetap bisa dipakai lagi
+	err = s.tx.InTx(ctx, func(r repository.TxRepos) error {
+		if err := r.Users.Create(ctx, u); err != nil {
+			if ae := apperr.FromPg(err); ae != nil {
+				// email didaftarkan (signup / undangan lain) setelah pengecekan di atas
+				if ae.Code == "duplicate" {
+					return apperr.Conflict("email sudah terdaftar, silakan login", err)
+				}
+				return ae
+			}
+			return apperr.Internal("gagal menyimpan user", err)
+		}
+		for _, name := range roles {
+			role, err := r.Roles.FindByName(ctx, name)
+			if err != nil {
+				return apperr.Internal("gagal mengambil role "+name, err)
+			}
+			if err := r.Roles.Grant(ctx, u.ID, role.ID); err != nil {
+				return apperr.Internal("gagal menambahkan role "+name, err)
+			}
+		}
+		// diklaim terakhir (atomik): token yang sama dipakai bersamaan = salah satu di-rollback
+		ok, err := r.Invitations.MarkAccepted(ctx, inv.ID, u.ID)
+		if err != nil {
+			return apperr.Internal("gagal menyimpan undangan", err)
+		}
+		if !ok {
+			return invalid
+		}
+		return nil
+	})
+	if err != nil {
+		var ae *apperr.AppError
+		if errors.As(err, &ae) {
+			return nil, ae
+		}
+		return nil, apperr.Internal("gagal menyimpan undangan", err)
+	}
+	u.Roles = roles
+	return u, nil
+}
+
+func (s *invitationSvc) findPending(ctx context.Context, id string) (*domain.Invitation, error) {
+	iid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperr.BadRequest("id tidak valid", err)
+	}
+	inv, err := s.invitations.FindPending(ctx, iid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("undangan tidak ditemukan", err)
+		}
+		return nil, apperr.Internal("gagal mengambil undangan", err)
+	}
+	return inv, nil
+}
+
+// sendInvitation: gagal kirim email hanya di-log; admin bisa kirim ulang
+func (s *invitationSvc) sendInvitation(ctx context.Context, inv *domain.Invitation, raw string) {
+	link := s.cfg.AppBaseURL + "/accept-invitation?token=" + url.QueryEscape(raw)
+	greeting := "Halo"
+	if inv.Name != "" {
+		greeting += " " + inv.Name
+	}
+	msg := mailer.Message{
+		To:      inv.Email,
+		Subject: "Undangan membuat akun",
+		Body: greeting + ",\n\n" +
+			"Anda diundang untuk membuat akun. Gunakan link berikut untuk mengatur nama dan password Anda (berlaku " + s.cfg.TTL.String() + "):\n" +
+			link + "\n\n" +
+			"Abaikan email ini jika Anda tidak mengenal undangan ini.\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("invitation %s: send mail: %v", inv.ID, err)
+	}
+}
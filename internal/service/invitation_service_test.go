@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/password"
+)
+
+func TestInvitationAcceptCreateErrors(t *testing.T) {
+	hasher, err := password.NewHasher(password.HasherConfig{
+		Argon2: password.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	})
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		// signup atau undangan lain untuk email yang sama selesai lebih dulu
+		{name: "email sudah terdaftar", err: errUniqueViolation, wantStatus: 409, wantMsg: "email sudah terdaftar, silakan login"},
+		{name: "error lain", err: errors.New("connection reset"), wantStatus: 500, wantMsg: "gagal menyimpan user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &fakeUserRepo{err: tt.err}
+			invitations := &fakeInvitationRepo{inv: &domain.Invitation{
+				ID: uuid.New(), Email: "budi@example.com", Name: "Budi", ExpiresAt: time.Now().Add(time.Hour),
+			}}
+			s := NewInvitationSvc(InvitationDeps{
+				Invitations: invitations,
+				Users:       users,
+				Tx:          fakeTransactor{repos: repository.TxRepos{Users: users, Invitations: invitations}},
+				Passwords:   &password.Policy{},
+				Hasher:      hasher,
+			}, nil, InvitationConfig{})
+
+			_, err := s.Accept(context.Background(), "token", "", "Kx9#vTq2!mWz")
+			var ae *apperr.AppError
+			if !errors.As(err, &ae) {
+				t.Fatalf("error = %v, want *apperr.AppError", err)
+			}
+			if ae.HTTPStatus != tt.wantStatus || ae.Message != tt.wantMsg {
+				t.Fatalf("error = %d %q, want %d %q", ae.HTTPStatus, ae.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
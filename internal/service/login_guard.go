@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+)
+
+// LoginGuard membatasi brute-force login per akun (email) dan per IP client.
+type LoginGuard interface {
+	// Check menolak percobaan jika akun terkunci, IP melewati batas, atau jeda progresif belum lewat
+	Check(ctx context.Context, email, ip string) error
+	// Fail mencatat login gagal; mengembalikan error account_locked jika kegagalan ini mengunci akun
+	Fail(ctx context.Context, email, ip string) error
+	// Succeed me-reset hitungan akun (hitungan IP tetap, supaya tidak bisa di-reset pakai akun sendiri)
+	Succeed(ctx context.Context, email string) error
+	// Unlock membuka kunci akun secara manual (admin)
+	Unlock(ctx context.Context, email string) error
+}
+
+type LoginGuardConfig struct {
+	MaxFailures     int           // gagal per akun sebelum dikunci; 0 = tidak pernah dikunci
+	LockoutDuration time.Duration // lama akun dikunci
+	IPMaxFailures   int           // gagal per IP dalam Window sebelum IP ditolak; 0 = nonaktif
+	Window          time.Duration // hitungan gagal di-reset setelah tidak ada gagal selama Window
+	DelayAfter      int           // jumlah gagal sebelum jeda progresif berlaku
+	DelayBase       time.Duration // jeda pertama, berlipat dua tiap gagal berikutnya; 0 = nonaktif
+}
+
+// batas atas jeda progresif
+const maxLoginDelay = 30 * time.Second
+
+type loginGuard struct {
+	repo repository.LoginAttemptRepository
+	cfg  LoginGuardConfig
+}
+
+func NewLoginGuard(repo repository.LoginAttemptRepository, cfg LoginGuardConfig) LoginGuard {
+	return &loginGuard{repo: repo, cfg: cfg}
+}
+
+func accountKey(email string) string { return "acct:" + email }
+func ipKey(ip string) string         { return "ip:" + ip }
+
+func (g *loginGuard) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	a, err := g.find(ctx, accountKey(email))
+	if err != nil {
+		return err
+	}
+	if a != nil {
+		if a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+			return apperr.AccountLocked("akun dikunci sementara karena terlalu banyak percobaan login gagal", a.LockedUntil.Sub(now))
+		}
+		if g.active(a, now) {
+			if wait := a.LastFailedAt.Add(g.delay(a.Failures)).Sub(now); wait > 0 {
+				return apperr.TooManyRequests("terlalu banyak percobaan login, coba lagi sebentar lagi", wait)
+			}
+		}
+	}
+
+	if ip == "" || g.cfg.IPMaxFailures <= 0 {
+		return nil
+	}
+	a, err = g.find(ctx, ipKey(ip))
+	if err != nil {
+		return err
+	}
+	if a != nil && g.active(a, now) && a.Failures >= g.cfg.IPMaxFailures {
+		return apperr.TooManyRequests("terlalu banyak percobaan login dari alamat ini, coba lagi nanti", a.LastFailedAt.Add(g.cfg.Window).Sub(now))
+	}
+	return nil
+}
+
+func (g *loginGuard) Fail(ctx context.Context, email, ip string) error {
+	if ip != "" {
+		if _, err := g.repo.RecordFailure(ctx, ipKey(ip), g.cfg.Window); err != nil {
+			return apperr.Internal("gagal mencatat percobaan login", err)
+		}
+	}
+
+	a, err := g.repo.RecordFailure(ctx, accountKey(email), g.cfg.Window)
+	if err != nil {
+		return apperr.Internal("gagal mencatat percobaan login", err)
+	}
+	if g.cfg.MaxFailures > 0 && a.Failures >= g.cfg.MaxFailures {
+		until := time.Now().Add(g.cfg.LockoutDuration)
+		if err := g.repo.Lock(ctx, accountKey(email), until); err != nil {
+			return apperr.Internal("gagal mengunci akun", err)
+		}
+		return apperr.AccountLocked("akun dikunci sementara karena terlalu banyak percobaan login gagal", g.cfg.LockoutDuration)
+	}
+	return nil
+}
+
+func (g *loginGuard) Succeed(ctx context.Context, email string) error {
+	if err := g.repo.Reset(ctx, accountKey(email)); err != nil {
+		return apperr.Internal("gagal reset percobaan login", err)
+	}
+	return nil
+}
+
+func (g *loginGuard) Unlock(ctx context.Context, email string) error {
+	return g.Succeed(ctx, email)
+}
+
+func (g *loginGuard) find(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	a, err := g.repo.Find(ctx, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperr.Internal("gagal membaca percobaan login", err)
+	}
+	return a, nil
+}
+
+// active: hitungan gagal masih berlaku (belum lewat window)
+func (g *loginGuard) active(a *domain.LoginAttempt, now time.Time) bool {
+	return now.Sub(a.LastFailedAt) < g.cfg.Window
+}
+
+// delay = DelayBase * 2^(failures-DelayAfter), maksimal maxLoginDelay
+func (g *loginGuard) delay(failures int) time.Duration {
+	if g.cfg.DelayBase <= 0 || failures < g.cfg.DelayAfter {
+		return 0
+	}
+	d := g.cfg.DelayBase
+	for i := g.cfg.DelayAfter; i < failures && d < maxLoginDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxLoginDelay)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/auth"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/secretbox"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/totp"
+)
+
+type MFAService interface {
+	Status(ctx context.Context, userID uuid.UUID) (*MFAStatus, error)
+	// SetupTOTP membuat secret baru (belum aktif sampai ConfirmTOTP)
+	SetupTOTP(ctx context.Context, userID uuid.UUID) (*TOTPSetup, error)
+	// ConfirmTOTP mengaktifkan TOTP dan mengembalikan recovery code (hanya ditampilkan sekali)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+
+	// dipakai authSvc saat login
+	Enabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	// Verify menerima kode TOTP atau recovery code
+	Verify(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	ConfirmedAt            *time.Time `json:"confirmed_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type TOTPSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFAConfig struct {
+	Issuer        string // nama aplikasi di authenticator
+	EncryptionKey string // untuk mengenkripsi secret TOTP di DB
+}
+
+const (
+	recoveryCodeCount = 10
+	// toleransi selisih jam: 1 step (30 detik) sebelum/sesudah
+	totpSkew = 1
+)
+
+type mfaSvc struct {
+	repo  repository.MFARepository
+	users repository.UserRepository
+	key   []byte
+	cfg   MFAConfig
+}
+
+func NewMFASvc(repo repository.MFARepository, users repository.UserRepository, cfg MFAConfig) MFAService {
+	return &mfaSvc{repo: repo, users: users, key: secretbox.KeyFrom(cfg.EncryptionKey), cfg: cfg}
+}
+
+func (s *mfaSvc) Status(ctx context.Context, userID uuid.UUID) (*MFAStatus, error) {
+	m, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || m.ConfirmedAt == nil {
+		return &MFAStatus{Enabled: false}, nil
+	}
+	n, err := s.repo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil recovery code", err)
+	}
+	return &MFAStatus{Enabled: true, ConfirmedAt: m.ConfirmedAt, RecoveryCodesRemaining: n}, nil
+}
+
+func (s *mfaSvc) SetupTOTP(ctx context.Context, userID uuid.UUID) (*TOTPSetup, error) {
+	m, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil && m.ConfirmedAt != nil {
+		return nil, apperr.Conflict("2FA sudah aktif, nonaktifkan terlebih dahulu", nil)
+	}
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil user", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperr.Internal("gagal membuat secret", err)
+	}
+	enc, err := secretbox.Seal(s.key, secret)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengenkripsi secret", err)
+	}
+	if err := s.repo.Save(ctx, &domain.UserMFA{UserID: userID, SecretEnc: enc}); err != nil {
+		return nil, apperr.Internal("gagal menyimpan 2FA", err)
+	}
+	return &TOTPSetup{Secret: secret, OTPAuthURI: totp.URI(s.cfg.Issuer, u.Email, secret)}, nil
+}
+
+func (s *mfaSvc) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	m, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, apperr.BadRequest("2FA belum di-setup", nil)
+	}
+	if m.ConfirmedAt != nil {
+		return nil, apperr.Conflict("2FA sudah aktif", nil)
+	}
+	step, ok, err := s.checkTOTP(m, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperr.Validation("kode 2FA tidak valid", nil)
+	}
+	if err := s.repo.Confirm(ctx, userID, step); err != nil {
+		return nil, apperr.Internal("gagal mengaktifkan 2FA", err)
+	}
+	return s.newRecoveryCodes(ctx, userID)
+}
+
+func (s *mfaSvc) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		return apperr.Internal("gagal menonaktifkan 2FA", err)
+	}
+	return nil
+}
+
+func (s *mfaSvc) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(ctx, userID)
+}
+
+func (s *mfaSvc) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m, err := s.find(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return m != nil && m.ConfirmedAt != nil, nil
+}
+
+func (s *mfaSvc) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	invalid := apperr.Unauthorized("kode 2FA tidak valid", nil)
+
+	m, err := s.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if m == nil || m.ConfirmedAt == nil {
+		return apperr.BadRequest("2FA belum aktif", nil)
+	}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return apperr.Validation("kode 2FA wajib diisi", nil)
+	}
+
+	// kode 6 digit → TOTP, selain itu dianggap recovery code
+	if len(code) == totp.Digits {
+		step, ok, err := s.checkTOTP(m, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return invalid
+		}
+		used, err := s.repo.UseStep(ctx, userID, step)
+		if err != nil {
+			return apperr.Internal("gagal menyimpan 2FA", err)
+		}
+		if !used {
+			return invalid // kode yang sama sudah dipakai
+		}
+		return nil
+	}
+
+	ok, err := s.repo.UseRecoveryCode(ctx, userID, auth.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return apperr.Internal("gagal memakai recovery code", err)
+	}
+	if !ok {
+		return invalid
+	}
+	return nil
+}
+
+// find mengembalikan nil (tanpa error) jika user belum pernah setup MFA
+func (s *mfaSvc) find(ctx context.Context, userID uuid.UUID) (*domain.UserMFA, error) {
+	m, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperr.Internal("gagal mengambil 2FA", err)
+	}
+	return m, nil
+}
+
+func (s *mfaSvc) checkTOTP(m *domain.UserMFA, code string) (int64, bool, error) {
+	secret, err := secretbox.Open(s.key, m.SecretEnc)
+	if err != nil {
+		return 0, false, apperr.Internal("gagal membaca secret 2FA", err)
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok || step <= m.LastUsedStep {
+		return 0, false, nil
+	}
+	return step, true, nil
+}
+
+func (s *mfaSvc) newRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		c, err := randomRecoveryCode()
+		if err != nil {
+			return nil, apperr.Internal("gagal membuat recovery code", err)
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, auth.HashToken(normalizeRecoveryCode(c)))
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, apperr.Internal("gagal menyimpan recovery code", err)
+	}
+	return codes, nil
+}
+
+// alfabet tanpa karakter yang mirip (0/O, 1/I/L)
+const recoveryAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// randomRecoveryCode menghasilkan kode format XXXXX-XXXXX
+func randomRecoveryCode() (string, error) {
+	// rejection sampling agar tiap karakter berpeluang sama
+	limit := byte(256 - 256%len(recoveryAlphabet))
+	out := make([]byte, 0, 11)
+	buf := make([]byte, 16)
+	for len(out) < 11 {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, v := range buf {
+			if len(out) == 11 {
+				break
+			}
+			if len(out) == 5 {
+				out = append(out, '-')
+			}
+			if v >= limit {
+				continue
+			}
+			out = append(out, recoveryAlphabet[int(v)%len(recoveryAlphabet)])
+		}
+	}
+	return string(out), nil
+}
+
+func normalizeRecoveryCode(c string) string {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	return strings.ReplaceAll(strings.ReplaceAll(c, "-", ""), " ", "")
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+)
+
+type RoleService interface {
+	List(ctx context.Context) ([]RoleInfo, error)
+	UserRoles(ctx context.Context, userID string) ([]string, error)
+	Grant(ctx context.Context, userID, role string) error
+	Revoke(ctx context.Context, userID, role string) error
+	// HasPermission dipakai middleware.RequirePermission
+	HasPermission(ctx context.Context, roles []string, perm string) (bool, error)
+	// Bootstrap seed role bawaan dan memberi role admin ke adminEmail (jika ada usernya)
+	Bootstrap(ctx context.Context, adminEmail string) error
+}
+
+type RoleInfo struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Permissions []string  `json:"permissions"`
+}
+
+// permission per role di-cache sebentar karena dicek di setiap request
+const rolePermCacheTTL = time.Minute
+
+type cachedPerms struct {
+	perms    []string
+	loadedAt time.Time
+}
+
+type roleSvc struct {
+	roles       repository.RoleRepository
+	users       repository.UserRepository
+	revocations repository.RevocationStore
+
+	mu    sync.RWMutex
+	cache map[string]cachedPerms
+}
+
+func NewRoleSvc(roles repository.RoleRepository, users repository.UserRepository, revocations repository.RevocationStore) RoleService {
+	return &roleSvc{roles: roles, users: users, revocations: revocations, cache: map[string]cachedPerms{}}
+}
+
+func (s *roleSvc) List(ctx context.Context) ([]RoleInfo, error) {
+	roles, err := s.roles.FindAll(ctx)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil role", err)
+	}
+	out := make([]RoleInfo, 0, len(roles))
+	for i := range roles {
+		out = append(out, RoleInfo{
+			ID:          roles[i].ID,
+			Name:        roles[i].Name,
+			Description: roles[i].Description,
+			Permissions: roles[i].PermissionNames(),
+		})
+	}
+	return out, nil
+}
+
+func (s *roleSvc) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	uid, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.roles.RolesForUser(ctx, uid)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil role user", err)
+	}
+	return roleNames(roles), nil
+}
+
+func (s *roleSvc) Grant(ctx context.Context, userID, role string) error {
+	uid, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	r, err := s.findRole(ctx, role)
+	if err != nil {
+		return err
+	}
+	if err := s.roles.Grant(ctx, uid, r.ID); err != nil {
+		return apperr.Internal("gagal menambahkan role", err)
+	}
+	return nil
+}
+
+func (s *roleSvc) Revoke(ctx context.Context, userID, role string) error {
+	uid, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	r, err := s.findRole(ctx, role)
+	if err != nil {
+		return err
+	}
+	if err := s.roles.Revoke(ctx, uid, r.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.NotFound("user tidak memiliki role tersebut", err)
+		}
+		return apperr.Internal("gagal mencabut role", err)
+	}
+	// access token lama masih membawa claim role → paksa user refresh token
+	if err := s.revocations.RevokeUser(ctx, uid, time.Now()); err != nil {
+		return apperr.Internal("gagal mencabut token", err)
+	}
+	return nil
+}
+
+func (s *roleSvc) HasPermission(ctx context.Context, roles []string, perm string) (bool, error) {
+	for _, role := range roles {
+		perms, err := s.permissionsOf(ctx, role)
+		if err != nil {
+			return false, err
+		}
+		for _, p := range perms {
+			if domain.PermissionMatches(p, perm) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *roleSvc) Bootstrap(ctx context.Context, adminEmail string) error {
+	if err := s.roles.EnsureDefaults(ctx, domain.DefaultRoles); err != nil {
+		return err
+	}
+	adminEmail = strings.ToLower(strings.TrimSpace(adminEmail))
+	if adminEmail == "" {
+		return nil
+	}
+	u, err := s.users.FindByEmail(ctx, adminEmail)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // user admin belum register
+		}
+		return err
+	}
+	admin, err := s.roles.FindByName(ctx, domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return s.roles.Grant(ctx, u.ID, admin.ID)
+}
+
+func (s *roleSvc) permissionsOf(ctx context.Context, role string) ([]string, error) {
+	s.mu.RLock()
+	c, ok := s.cache[role]
+	s.mu.RUnlock()
+	if ok && time.Since(c.loadedAt) < rolePermCacheTTL {
+		return c.perms, nil
+	}
+
+	perms, err := s.roles.PermissionsForRoles(ctx, []string{role})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[role] = cachedPerms{perms: perms, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return perms, nil
+}
+
+func (s *roleSvc) findUser(ctx context.Context, userID string) (uuid.UUID, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, apperr.BadRequest("id tidak valid", err)
+	}
+	if _, err := s.users.FindByID(ctx, uid); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, apperr.NotFound("user tidak ditemukan", err)
+		}
+		return uuid.Nil, apperr.Internal("gagal mengambil user", err)
+	}
+	return uid, nil
+}
+
+func (s *roleSvc) findRole(ctx context.Context, name string) (*domain.Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, apperr.Validation("role wajib diisi", nil)
+	}
+	r, err := s.roles.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("role tidak ditemukan", err)
+		}
+		return nil, apperr.Internal("gagal mengambil role", err)
+	}
+	return r, nil
+}
+
+func roleNames(roles []domain.Role) []string {
+	out := make([]string, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, r.Name)
+	}
+	return out
+}
+
+// rolesGrant: true jika salah satu role memiliki permission perm (tanpa cache, untuk cek sekali jalan)
+func rolesGrant(ctx context.Context, repo repository.RoleRepository, names []string, perm string) (bool, error) {
+	perms, err := repo.PermissionsForRoles(ctx, names)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range perms {
+		if domain.PermissionMatches(p, perm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+)
+
+type SessionService interface {
+	// List mengembalikan session aktif user; session currentID ditandai Current
+	List(ctx context.Context, userID, currentID uuid.UUID) ([]domain.Session, error)
+	// Revoke mencabut session beserta refresh token-nya
+	Revoke(ctx context.Context, userID uuid.UUID, id string) error
+	// CheckSession dipakai middleware.AuthBearer untuk claim "sid": session harus aktif dan akun
+	// pemiliknya tidak di-suspend / dinonaktifkan (satu query). Sekalian mencatat last_seen_at.
+	CheckSession(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type sessionSvc struct {
+	repo    repository.SessionRepository
+	refresh repository.RefreshTokenRepository
+}
+
+func NewSessionSvc(repo repository.SessionRepository, refresh repository.RefreshTokenRepository) SessionService {
+	return &sessionSvc{repo: repo, refresh: refresh}
+}
+
+func (s *sessionSvc) List(ctx context.Context, userID, currentID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := s.repo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, apperr.Internal("gagal mengambil session", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+func (s *sessionSvc) Revoke(ctx context.Context, userID uuid.UUID, id string) error {
+	sid, err := uuid.Parse(id)
+	if err != nil {
+		return apperr.BadRequest("id tidak valid", err)
+	}
+	ok, err := s.repo.Revoke(ctx, userID, sid)
+	if err != nil {
+		return apperr.Internal("gagal mencabut session", err)
+	}
+	if !ok {
+		return apperr.NotFound("session tidak ditemukan", nil)
+	}
+	if err := s.refresh.RevokeFamily(ctx, sid); err != nil {
+		return apperr.Internal("gagal mencabut refresh token", err)
+	}
+	return nil
+}
+
+func (s *sessionSvc) CheckSession(ctx context.Context, id, userID uuid.UUID) error {
+	a, err := s.repo.FindActive(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New("session_revoked", 401, "session sudah berakhir, silakan login ulang", err)
+		}
+		return apperr.Internal("gagal mengecek session", err)
+	}
+	if err := accountStatusError(&domain.User{Status: a.Status, SuspendedUntil: a.SuspendedUntil}); err != nil {
+		return err
+	}
+	// Touch hanya jika sudah lewat semenit; gagal mencatat tidak boleh menggagalkan request
+	if time.Since(a.LastSeenAt) > time.Minute {
+		if err := s.repo.Touch(ctx, id); err != nil {
+			log.Printf("session %s: gagal update last_seen_at: %v", id, err)
+		}
+	}
+	return nil
+}
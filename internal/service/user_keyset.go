@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/filter"
+)
+
+// userCursor = isi cursor. Sort & filter ikut disimpan supaya cursor tidak dipakai
+// dengan query yang berbeda (posisinya tidak bermakna di urutan lain).
+type userCursor struct {
+	Sort     string    `json:"s"` // filter.SortString
+	Q        string    `json:"q,omitempty"`
+	Mode     string    `json:"m,omitempty"` // search mode
+	Status   string    `json:"st,omitempty"`
+	Filters  string    `json:"f,omitempty"` // filter.Canonical
+	Values   []string  `json:"v"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"` // true = prev_cursor
+}
+
+// listKeyset: keyset pagination berdasarkan (kolom sort..., id). Tidak terpengaruh baris yang
+// ditambah / dihapus di antara halaman dan tidak butuh COUNT kecuali WithTotal.
+func (s *userSvc) listKeyset(ctx context.Context, p ListUsersParams, o repository.UserListOptions) (CursorPage[domain.User], error) {
+	k := repository.UserKeyset{Limit: p.PageSize + 1}
+	if p.Cursor != "" {
+		var c userCursor
+		if err := s.cursors.Decode(p.Cursor, &c); err != nil {
+			return CursorPage[domain.User]{}, apperr.BadRequest("cursor tidak valid", err)
+		}
+		if c.Sort != filter.SortString(o.Sort) || c.Q != p.Q || c.Mode != p.SearchMode || c.Status != p.Status ||
+			c.Filters != filter.Canonical(p.Filters) {
+			return CursorPage[domain.User]{}, apperr.BadRequest("cursor tidak cocok dengan parameter sort / filter", nil)
+		}
+		k.After = &repository.UserKeysetPos{Values: c.Values, ID: c.ID}
+		k.Backward = c.Backward
+	}
+
+	items, err := s.repo.FindKeyset(ctx, p.userFilter(), o, k)
+	if err != nil {
+		return CursorPage[domain.User]{}, listError(err)
+	}
+
+	// baris ekstra (Limit+1) hanya penanda masih ada halaman berikutnya di arah yang diambil
+	more := len(items) > p.PageSize
+	if more {
+		if k.Backward {
+			items = items[1:]
+		} else {
+			items = items[:p.PageSize]
+		}
+	}
+	out := CursorPage[domain.User]{Items: items, PageSize: p.PageSize}
+	if k.Backward {
+		out.HasPrev, out.HasNext = more, true
+	} else {
+		out.HasPrev, out.HasNext = p.Cursor != "", more
+	}
+
+	if len(items) > 0 {
+		if out.HasNext {
+			if out.NextCursor, err = s.encodeCursor(p, o, &items[len(items)-1], false); err != nil {
+				return CursorPage[domain.User]{}, err
+			}
+		}
+		if out.HasPrev {
+			if out.PrevCursor, err = s.encodeCursor(p, o, &items[0], true); err != nil {
+				return CursorPage[domain.User]{}, err
+			}
+		}
+	}
+
+	if p.WithTotal {
+		total, err := s.repo.CountFiltered(ctx, p.userFilter())
+		if err != nil {
+			return CursorPage[domain.User]{}, listError(err)
+		}
+		out.Total = &total
+	}
+	return out, nil
+}
+
+func (s *userSvc) encodeCursor(p ListUsersParams, o repository.UserListOptions, u *domain.User, backward bool) (string, error) {
+	raw, err := s.cursors.Encode(userCursor{
+		Sort:     filter.SortString(o.Sort),
+		Q:        p.Q,
+		Mode:     p.SearchMode,
+		Status:   p.Status,
+		Filters:  filter.Canonical(p.Filters),
+		Values:   repository.UserKeysetValues(u, o.Sort),
+		ID:       u.ID,
+		Backward: backward,
+	})
+	if err != nil {
+		return "", apperr.Internal("gagal membuat cursor", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunUserPurge menghapus permanen user yang sudah di-soft delete lebih lama dari retention,
+// sekali saat start lalu setiap interval, sampai ctx selesai. Aman dijalankan di beberapa
+// instance sekaligus (DELETE idempotent).
+func RunUserPurge(ctx context.Context, users UserService, interval, retention time.Duration) {
+	purge := func() {
+		n, err := users.PurgeDeleted(ctx, retention)
+		if err != nil {
+			log.Printf("purge user terhapus: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("purge user terhapus: %d user dihapus permanen", n)
+		}
+	}
+
+	purge()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			purge()
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -13,6 +15,8 @@ import (
 	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
 	"github.com/ariyaagustian/gin-boilerplate/internal/repository"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/cursor"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/filter"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/validation"
 )
 
@@ -20,22 +24,34 @@ const MaxPageSize = 100
 
 type UserService interface {
 	Create(ctx context.Context, name, email string) (*domain.User, error)
+	// List = pagination offset; pakai ListCursor jika p.IsCursor()
 	List(ctx context.Context, p ListUsersParams) (PageResult[domain.User], error)
-	Get(ctx context.Context, id string) (*domain.User, error)
-	Update(ctx context.Context, id, name, email string) (*domain.User, error)
-	Delete(ctx context.Context, id string) error
+	// ListCursor = keyset pagination (pagination=cursor)
+	ListCursor(ctx context.Context, p ListUsersParams) (CursorPage[domain.User], error)
+	// Get: fields = sparse fieldset (nama field JSON); kosong = semua field
+	Get(ctx context.Context, id string, fields []string) (*domain.User, error)
+	// Update & Delete: version > 0 = versi dari If-Match, harus sama dengan versi user saat ini (412 jika tidak)
+	Update(ctx context.Context, id, name, email string, version int64) (*domain.User, error)
+	// Delete = soft delete; token user langsung dicabut, data dihapus permanen oleh PurgeDeleted
+	Delete(ctx context.Context, id string, version int64) error
+	ListDeleted(ctx context.Context, page, pageSize int) (PageResult[domain.User], error)
+	Restore(ctx context.Context, id string) (*domain.User, error)
+	// PurgeDeleted menghapus permanen user yang sudah dihapus lebih lama dari retention
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 type userSvc struct {
-	repo repository.UserRepository
-	v    *validator.Validate
+	repo        repository.UserRepository
+	revocations repository.RevocationStore
+	cursors     *cursor.Codec
+	v           *validator.Validate
 }
 
-func NewUserSvc(r repository.UserRepository, v *validator.Validate) *userSvc {
+func NewUserSvc(r repository.UserRepository, revocations repository.RevocationStore, cursors *cursor.Codec, v *validator.Validate) *userSvc {
 	if v == nil {
 		v = validator.New()
 	}
-	return &userSvc{repo: r, v: v}
+	return &userSvc{repo: r, revocations: revocations, cursors: cursors, v: v}
 }
 
 type createUserDTO struct {
@@ -44,11 +60,35 @@ type createUserDTO struct {
 }
 
 type ListUsersParams struct {
-	Q        string
-	Page     int
-	PageSize int
-	SortBy   string // "created_at","name","email"
-	SortDir  string // "asc" / "desc"
+	Q          string
+	SearchMode string             // "contains" (default) / "fuzzy", lihat repository.SearchFuzzy
+	Status     string             // "", "active", "suspended", "disabled"
+	Filters    []filter.Condition // filter[field][op]=value (lihat filter.Parse)
+	Page       int
+	PageSize   int
+	SortBy     string // "created_at","name","email","relevance" (butuh Q, hanya mode offset)
+	SortDir    string // "asc" / "desc"
+	// Sort = multi kolom, mis. "-created_at,name" ("-" = menurun); terisi = SortBy / SortDir diabaikan
+	Sort string
+	// Fields = sparse fieldset (nama field JSON); kolom lain tidak diambil dari DB
+	Fields []string
+
+	// Pagination: "offset" (default, pakai Page) atau "cursor" (keyset, pakai Cursor).
+	// Cursor terisi otomatis berarti mode cursor.
+	Pagination string
+	Cursor     string
+	// WithTotal menghitung total (COUNT) di mode cursor; mode offset selalu menghitung
+	WithTotal bool
+}
+
+const (
+	PaginationOffset = "offset"
+	PaginationCursor = "cursor"
+)
+
+// IsCursor: mode cursor dipilih lewat pagination=cursor atau cursor yang terisi
+func (p ListUsersParams) IsCursor() bool {
+	return p.Cursor != "" || p.Pagination == PaginationCursor
 }
 
 type PageResult[T any] struct {
@@ -60,6 +100,42 @@ type PageResult[T any] struct {
 	HasNext    bool  `json:"has_next"`
 }
 
+// CursorPage = hasil pagination=cursor; tanpa page / total_pages, total hanya jika diminta
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	PageSize   int    `json:"page_size"`
+	Total      *int64 `json:"total,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// WithItems = page yang sama dengan item lain, mis. hasil sparse fieldset
+func WithItems[T, U any](p PageResult[T], items []U) PageResult[U] {
+	return PageResult[U]{
+		Items:      items,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		Total:      p.Total,
+		TotalPages: p.TotalPages,
+		HasNext:    p.HasNext,
+	}
+}
+
+// WithCursorItems = WithItems untuk CursorPage
+func WithCursorItems[T, U any](p CursorPage[T], items []U) CursorPage[U] {
+	return CursorPage[U]{
+		Items:      items,
+		PageSize:   p.PageSize,
+		Total:      p.Total,
+		HasNext:    p.HasNext,
+		HasPrev:    p.HasPrev,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+	}
+}
+
 func (s *userSvc) Create(ctx context.Context, name, email string) (*domain.User, error) {
 	name = strings.TrimSpace(name)
 	email = strings.ToLower(strings.TrimSpace(email))
@@ -71,22 +147,52 @@ func (s *userSvc) Create(ctx context.Context, name, email string) (*domain.User,
 
 	u := &domain.User{Name: dto.Name, Email: dto.Email}
 	if err := s.repo.Create(ctx, u); err != nil {
-		// 1) Heuristik pesan duplicate
-		if strings.Contains(err.Error(), "duplicate key value") {
-			return nil, apperr.Conflict("email sudah terdaftar, gunakan email lain", err)
-		}
-		// 2) Mapping kode PG
+		// 1) Mapping kode PG, duplicate = email sudah dipakai
 		if ae := apperr.FromPg(err); ae != nil {
+			if ae.Code == "duplicate" {
+				return nil, apperr.Conflict("email sudah terdaftar, gunakan email lain", err)
+			}
 			return nil, ae
 		}
-		// 3) Lainnya
+		// 2) Lainnya
 		return nil, apperr.Internal("gagal menyimpan data", err)
 	}
 	return u, nil
 }
 
 func (s *userSvc) List(ctx context.Context, p ListUsersParams) (PageResult[domain.User], error) {
-	// Normalisasi pagination
+	p, o, err := p.normalize()
+	if err != nil {
+		return PageResult[domain.User]{}, err
+	}
+
+	items, total, err := s.repo.FindPaged(ctx, p.userFilter(), o, p.Page, p.PageSize)
+	if err != nil {
+		return PageResult[domain.User]{}, listError(err)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(p.PageSize)))
+	return PageResult[domain.User]{
+		Items:      items,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    p.Page < totalPages,
+	}, nil
+}
+
+func (s *userSvc) ListCursor(ctx context.Context, p ListUsersParams) (CursorPage[domain.User], error) {
+	p.Pagination = PaginationCursor
+	p, o, err := p.normalize()
+	if err != nil {
+		return CursorPage[domain.User]{}, err
+	}
+	return s.listKeyset(ctx, p, o)
+}
+
+// normalize mengisi default pagination dan memvalidasi parameter yang sama untuk kedua mode
+func (p ListUsersParams) normalize() (ListUsersParams, repository.UserListOptions, error) {
 	if p.Page <= 0 {
 		p.Page = 1
 	}
@@ -96,36 +202,81 @@ func (s *userSvc) List(ctx context.Context, p ListUsersParams) (PageResult[domai
 		p.PageSize = MaxPageSize
 	}
 
-	// Default sorting
-	if p.SortBy == "" {
-		p.SortBy = "created_at"
+	switch p.Status {
+	case "", domain.UserStatusActive, domain.UserStatusSuspended, domain.UserStatusDisabled:
+	default:
+		return p, repository.UserListOptions{}, apperr.Validation("status tidak dikenal (active|suspended|disabled)", nil)
+	}
+
+	switch p.SearchMode {
+	case "", repository.SearchContains, repository.SearchFuzzy:
+	default:
+		return p, repository.UserListOptions{}, apperr.Validation("search_mode tidak dikenal (contains|fuzzy)", nil)
 	}
-	if p.SortDir != "asc" && p.SortDir != "desc" {
-		p.SortDir = "desc"
+
+	switch p.Pagination {
+	case "", PaginationOffset, PaginationCursor:
+	default:
+		return p, repository.UserListOptions{}, apperr.Validation("pagination tidak dikenal (offset|cursor)", nil)
 	}
 
-	items, total, err := s.repo.FindPaged(ctx, p.Q, p.Page, p.PageSize, p.SortBy, p.SortDir)
+	sort, err := p.sortKeys()
 	if err != nil {
-		return PageResult[domain.User]{}, err
+		return p, repository.UserListOptions{}, listError(err)
+	}
+	if slices.ContainsFunc(sort, func(k filter.SortKey) bool { return k.Field == repository.SortRelevance }) {
+		if strings.TrimSpace(p.Q) == "" {
+			return p, repository.UserListOptions{}, apperr.Validation("sort relevance butuh parameter q", nil)
+		}
+		if p.IsCursor() {
+			return p, repository.UserListOptions{}, apperr.Validation("sort relevance tidak didukung di pagination=cursor", nil)
+		}
+	}
+	fields, err := repository.ParseUserFields(p.Fields)
+	if err != nil {
+		return p, repository.UserListOptions{}, listError(err)
+	}
+	return p, repository.UserListOptions{Sort: sort, Fields: fields}, nil
+}
+
+// sortKeys: Sort jika berisi kolom, selain itu SortBy / SortDir (kolom tidak dikenal jatuh ke
+// created_at seperti perilaku lama sort_by). Hasilnya tidak pernah kosong, jadi cursor dan
+// repository selalu memakai kolom sort yang sama.
+func (p ListUsersParams) sortKeys() ([]filter.SortKey, error) {
+	if keys, err := repository.ParseUserSort(p.Sort); err != nil || len(keys) > 0 {
+		return keys, err
 	}
+	keys, err := repository.ParseUserSort(p.SortBy)
+	if err != nil || len(keys) != 1 {
+		keys = []filter.SortKey{{Field: "created_at"}}
+	}
+	keys[0].Desc = p.SortDir != "asc"
+	return keys, nil
+}
 
-	totalPages := int(math.Ceil(float64(total) / float64(p.PageSize)))
-	return PageResult[domain.User]{
-		Items:      items,
-		Page:       p.Page,
-		PageSize:   p.PageSize,
-		Total:      total,
-		TotalPages: totalPages,
-		HasNext:    p.Page < totalPages,
-	}, nil
+func (p ListUsersParams) userFilter() repository.UserFilter {
+	return repository.UserFilter{Q: p.Q, SearchMode: p.SearchMode, Status: p.Status, Where: p.Filters}
 }
 
-func (s *userSvc) Get(ctx context.Context, id string) (*domain.User, error) {
+// listError: filter / sort / fields yang tidak valid = kesalahan input, sisanya error internal
+func listError(err error) error {
+	var fe *filter.Error
+	if errors.As(err, &fe) {
+		return apperr.Validation(fe.Error(), err)
+	}
+	return apperr.Internal("gagal mengambil data", err)
+}
+
+func (s *userSvc) Get(ctx context.Context, id string, fields []string) (*domain.User, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, apperr.BadRequest("id tidak valid", err)
 	}
-	u, err := s.repo.FindByID(ctx, uid)
+	fields, err = repository.ParseUserFields(fields)
+	if err != nil {
+		return nil, listError(err)
+	}
+	u, err := s.repo.FindByID(ctx, uid, fields...)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperr.NotFound("user tidak ditemukan", err)
@@ -135,7 +286,7 @@ func (s *userSvc) Get(ctx context.Context, id string) (*domain.User, error) {
 	return u, nil
 }
 
-func (s *userSvc) Update(ctx context.Context, id, name, email string) (*domain.User, error) {
+func (s *userSvc) Update(ctx context.Context, id, name, email string, version int64) (*domain.User, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, apperr.BadRequest("id tidak valid", err)
@@ -148,6 +299,9 @@ func (s *userSvc) Update(ctx context.Context, id, name, email string) (*domain.U
 		}
 		return nil, apperr.Internal("gagal mengambil data", err)
 	}
+	if version > 0 && u.Version != version {
+		return nil, errVersionMismatch(nil)
+	}
 
 	if name != "" {
 		u.Name = strings.TrimSpace(name)
@@ -161,10 +315,14 @@ func (s *userSvc) Update(ctx context.Context, id, name, email string) (*domain.U
 	}
 
 	if err := s.repo.Update(ctx, u); err != nil {
-		if strings.Contains(err.Error(), "duplicate key value") {
-			return nil, apperr.Conflict("email sudah terdaftar, gunakan email lain", err)
+		// diubah request lain di antara FindByID dan Update
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errVersionMismatch(err)
 		}
 		if ae := apperr.FromPg(err); ae != nil {
+			if ae.Code == "duplicate" {
+				return nil, apperr.Conflict("email sudah terdaftar, gunakan email lain", err)
+			}
 			return nil, ae
 		}
 		return nil, apperr.Internal("gagal menyimpan data", err)
@@ -172,16 +330,80 @@ func (s *userSvc) Update(ctx context.Context, id, name, email string) (*domain.U
 	return u, nil
 }
 
-func (s *userSvc) Delete(ctx context.Context, id string) error {
+func (s *userSvc) Delete(ctx context.Context, id string, version int64) error {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return apperr.BadRequest("id tidak valid", err)
 	}
-	if err := s.repo.Delete(ctx, uid); err != nil {
+	if err := s.repo.Delete(ctx, uid, version); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return errVersionMismatch(err)
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return apperr.NotFound("user tidak ditemukan", err)
 		}
 		return apperr.Internal("gagal menghapus data", err)
 	}
+	// access token yang masih berlaku ikut ditolak; refresh / API key gagal karena user tidak ditemukan
+	if err := s.revocations.RevokeUser(ctx, uid, time.Now()); err != nil {
+		return apperr.Internal("gagal mencabut token", err)
+	}
 	return nil
 }
+
+func errVersionMismatch(err error) error {
+	return apperr.PreconditionFailed("data user sudah berubah, ambil ulang lalu coba lagi", err)
+}
+
+func (s *userSvc) ListDeleted(ctx context.Context, page, pageSize int) (PageResult[domain.User], error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	items, total, err := s.repo.FindDeletedPaged(ctx, page, pageSize)
+	if err != nil {
+		return PageResult[domain.User]{}, apperr.Internal("gagal mengambil data", err)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+	return PageResult[domain.User]{
+		Items:      items,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+	}, nil
+}
+
+func (s *userSvc) Restore(ctx context.Context, id string) (*domain.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperr.BadRequest("id tidak valid", err)
+	}
+	ok, err := s.repo.Restore(ctx, uid)
+	if err != nil {
+		// email sudah dipakai user baru sejak user ini dihapus
+		if ae := apperr.FromPg(err); ae != nil && ae.Code == "duplicate" {
+			return nil, apperr.Conflict("email user ini sudah dipakai user lain", err)
+		}
+		return nil, apperr.Internal("gagal memulihkan user", err)
+	}
+	if !ok {
+		return nil, apperr.NotFound("user terhapus tidak ditemukan", nil)
+	}
+	return s.Get(ctx, id, nil)
+}
+
+func (s *userSvc) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	n, err := s.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, apperr.Internal("gagal menghapus permanen user", err)
+	}
+	return n, nil
+}
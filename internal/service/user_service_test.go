@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+)
+
+func TestUserSvcDuplicateEmail(t *testing.T) {
+	id := uuid.NewString()
+	tests := []struct {
+		name       string
+		err        error
+		call       func(s *userSvc) error
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name: "create duplicate",
+			err:  errUniqueViolation,
+			call: func(s *userSvc) error {
+				_, err := s.Create(context.Background(), "Budi", "budi@example.com")
+				return err
+			},
+			wantStatus: 409,
+			wantMsg:    "email sudah terdaftar, gunakan email lain",
+		},
+		{
+			name: "update duplicate",
+			err:  errUniqueViolation,
+			call: func(s *userSvc) error {
+				_, err := s.Update(context.Background(), id, "", "budi@example.com", 0)
+				return err
+			},
+			wantStatus: 409,
+			wantMsg:    "email sudah terdaftar, gunakan email lain",
+		},
+		{
+			name: "restore duplicate",
+			err:  errUniqueViolation,
+			call: func(s *userSvc) error {
+				_, err := s.Restore(context.Background(), id)
+				return err
+			},
+			wantStatus: 409,
+			wantMsg:    "email user ini sudah dipakai user lain",
+		},
+		{
+			name: "update error lain",
+			err:  errors.New("connection reset"),
+			call: func(s *userSvc) error {
+				_, err := s.Update(context.Background(), id, "", "budi@example.com", 0)
+				return err
+			},
+			wantStatus: 500,
+			wantMsg:    "gagal menyimpan data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{user: &domain.User{Name: "Budi", Email: "lama@example.com", Version: 1}, err: tt.err}
+			err := tt.call(NewUserSvc(repo, nil, nil, nil))
+			var ae *apperr.AppError
+			if !errors.As(err, &ae) {
+				t.Fatalf("error = %v, want *apperr.AppError", err)
+			}
+			if ae.HTTPStatus != tt.wantStatus || ae.Message != tt.wantMsg {
+				t.Fatalf("error = %d %q, want %d %q", ae.HTTPStatus, ae.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
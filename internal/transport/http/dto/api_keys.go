@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name"                 example:"ci-deploy"`
+	Scopes    []string   `json:"scopes,omitempty"     example:"users:read"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-12-31T00:00:00Z"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id"                     example:"2b1c7f0e-..."`
+	Name       string     `json:"name"                   example:"ci-deploy"`
+	Prefix     string     `json:"prefix"                 example:"gbk_1a2b3c4d5e6f"`
+	Scopes     []string   `json:"scopes"                 example:"users:read"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"   example:"2026-12-31T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2026-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at"             example:"2026-01-01T00:00:00Z"`
+}
+
+type ListAPIKeysResp struct {
+	Data []APIKey `json:"data"`
+}
+
+// CreateAPIKeyResp: Key hanya ditampilkan sekali
+type CreateAPIKeyResp struct {
+	APIKey
+	Key string `json:"key" example:"gbk_1a2b3c4d5e6f_..."`
+}
@@ -7,16 +7,19 @@ type RegisterReq struct {
 }
 
 type User struct {
-	ID    string `json:"id"    example:"8d7a9b6e-..."`
-	Name  string `json:"name"  example:"Ariya"`
-	Email string `json:"email" example:"user@mail.com"`
-	Role  string `json:"role"  example:"user"`
+	ID              string   `json:"id"                example:"8d7a9b6e-..."`
+	Name            string   `json:"name"              example:"Ariya"`
+	Email           string   `json:"email"             example:"user@mail.com"`
+	EmailVerifiedAt *string  `json:"email_verified_at" example:"2025-01-01T00:00:00Z"`
+	Roles           []string `json:"roles"             example:"user"`
 }
 
 type RegisterResp struct {
-	User      User   `json:"user"`
-	Token     string `json:"token"      example:"eyJhbGciOiJI..."`
-	TokenType string `json:"token_type" example:"Bearer"`
+	User         User   `json:"user"`
+	Token        string `json:"token"         example:"eyJhbGciOiJI..."`
+	TokenType    string `json:"token_type"    example:"Bearer"`
+	RefreshToken string `json:"refresh_token" example:"q1N0b2tlbi1yYW5kb20..."`
+	ExpiresIn    int64  `json:"expires_in"    example:"900"`
 }
 type LoginReq struct {
 	Email    string `json:"email"    example:"user@mail.com"`
@@ -24,9 +27,19 @@ type LoginReq struct {
 }
 
 type TokenResp struct {
-	User      User   `json:"user"`
-	Token     string `json:"token"      example:"eyJhbGciOiJI..."`
-	TokenType string `json:"token_type" example:"Bearer"`
+	User         User   `json:"user"`
+	Token        string `json:"token"         example:"eyJhbGciOiJI..."`
+	TokenType    string `json:"token_type"    example:"Bearer"`
+	RefreshToken string `json:"refresh_token" example:"q1N0b2tlbi1yYW5kb20..."`
+	ExpiresIn    int64  `json:"expires_in"    example:"900"`
+}
+
+type RefreshReq struct {
+	RefreshToken string `json:"refresh_token" example:"q1N0b2tlbi1yYW5kb20..."`
+}
+
+type LogoutReq struct {
+	RefreshToken string `json:"refresh_token" example:"q1N0b2tlbi1yYW5kb20..."`
 }
 
 type SetPasswordReq struct {
@@ -37,3 +50,43 @@ type SetPasswordReq struct {
 type SetPasswordResp struct {
 	Message string `json:"message" example:"password updated"`
 }
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" example:"user@mail.com"`
+}
+
+type MagicLinkReq struct {
+	Email string `json:"email" example:"user@mail.com"`
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token"        example:"q1N0b2tlbi1yYW5kb20..."`
+	NewPassword string `json:"new_password" example:"newsecret123"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token" example:"q1N0b2tlbi1yYW5kb20..."`
+}
+
+type ResendVerificationReq struct {
+	Email string `json:"email" example:"user@mail.com"`
+}
+
+type MessageResp struct {
+	Message string `json:"message" example:"ok"`
+}
+
+type MFARequiredResp struct {
+	MFARequired bool   `json:"mfa_required" example:"true"`
+	MFAToken    string `json:"mfa_token"    example:"q1N0b2tlbi1yYW5kb20..."`
+	ExpiresIn   int64  `json:"expires_in"   example:"300"`
+}
+
+type MFAVerifyReq struct {
+	MFAToken string `json:"mfa_token" example:"q1N0b2tlbi1yYW5kb20..."`
+	Code     string `json:"code"      example:"123456"`
+}
+
+type OAuthStartResp struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.example.com/authorize?client_id=..."`
+}
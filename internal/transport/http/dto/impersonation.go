@@ -0,0 +1,13 @@
+package dto
+
+type ImpersonateReq struct {
+	Reason string `json:"reason" example:"Tiket #1234: reproduksi error checkout"`
+}
+
+type ImpersonateResp struct {
+	User           User   `json:"user"`
+	Token          string `json:"token"           example:"eyJhbGciOiJI..."`
+	TokenType      string `json:"token_type"      example:"Bearer"`
+	ExpiresIn      int64  `json:"expires_in"      example:"900"`
+	ImpersonatorID string `json:"impersonator_id" example:"8d7a9b6e-..."`
+}
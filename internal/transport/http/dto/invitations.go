@@ -0,0 +1,28 @@
+package dto
+
+type CreateInvitationReq struct {
+	Email string `json:"email"          example:"new.user@mail.com"`
+	Name  string `json:"name,omitempty" example:"Budi"`
+	Role  string `json:"role,omitempty" example:"admin"`
+}
+
+type Invitation struct {
+	ID        string `json:"id"             example:"2b1c7f0e-..."`
+	Email     string `json:"email"          example:"new.user@mail.com"`
+	Name      string `json:"name,omitempty" example:"Budi"`
+	Role      string `json:"role,omitempty" example:"admin"`
+	InvitedBy string `json:"invited_by"     example:"8d7a9b6e-..."`
+	ExpiresAt string `json:"expires_at"     example:"2026-01-08T00:00:00Z"`
+	SentAt    string `json:"sent_at"        example:"2026-01-01T00:00:00Z"`
+	CreatedAt string `json:"created_at"     example:"2026-01-01T00:00:00Z"`
+}
+
+type ListInvitationsResp struct {
+	Data []Invitation `json:"data"`
+}
+
+type AcceptInvitationReq struct {
+	Token    string `json:"token"          example:"q1N0b2tlbi1yYW5kb20..."`
+	Name     string `json:"name,omitempty" example:"Budi Santoso"`
+	Password string `json:"password"       example:"secret123"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type MFAStatusResp struct {
+	Enabled                bool   `json:"enabled"                  example:"true"`
+	ConfirmedAt            string `json:"confirmed_at,omitempty"   example:"2025-01-01T00:00:00Z"`
+	RecoveryCodesRemaining int    `json:"recovery_codes_remaining" example:"10"`
+}
+
+type TOTPSetupResp struct {
+	Secret     string `json:"secret"      example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/App:user@mail.com?secret=JBSWY3DPEHPK3PXP&issuer=App"`
+}
+
+type MFACodeReq struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type RecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"ABCDE-FGHJK"`
+}
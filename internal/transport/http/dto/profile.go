@@ -0,0 +1,18 @@
+package dto
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" example:"secret123"`
+	NewPassword     string `json:"new_password"     example:"n3w-s3cret"`
+}
+
+// UpdateProfileReq: field yang tidak dikirim tidak diubah
+type UpdateProfileReq struct {
+	Name  *string `json:"name,omitempty"  example:"Ariya"`
+	Email *string `json:"email,omitempty" example:"new@mail.com"`
+}
+
+type UpdateProfileResp struct {
+	User User `json:"user"`
+	// terisi jika email baru menunggu konfirmasi lewat link yang dikirim ke alamat tsb
+	PendingEmail string `json:"pending_email,omitempty" example:"new@mail.com"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type Role struct {
+	ID          string   `json:"id"          example:"2b1c7f0e-..."`
+	Name        string   `json:"name"        example:"admin"`
+	Description string   `json:"description" example:"Administrator"`
+	Permissions []string `json:"permissions" example:"users:read,users:write"`
+}
+
+type ListRolesResp struct {
+	Data []Role `json:"data"`
+}
+
+type UserRolesResp struct {
+	Data []string `json:"data" example:"admin,user"`
+}
+
+type GrantRoleReq struct {
+	Role string `json:"role" example:"admin"`
+}
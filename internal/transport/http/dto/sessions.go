@@ -0,0 +1,15 @@
+package dto
+
+type Session struct {
+	ID         string `json:"id"           example:"2b1c7f0e-..."`
+	UserAgent  string `json:"user_agent"   example:"Mozilla/5.0 ..."`
+	IP         string `json:"ip"           example:"203.0.113.10"`
+	CreatedAt  string `json:"created_at"   example:"2026-01-01T00:00:00Z"`
+	LastSeenAt string `json:"last_seen_at" example:"2026-01-01T01:00:00Z"`
+	ExpiresAt  string `json:"expires_at"   example:"2026-01-31T01:00:00Z"`
+	Current    bool   `json:"current"      example:"true"`
+}
+
+type ListSessionsResp struct {
+	Data []Session `json:"data"`
+}
@@ -1,10 +1,19 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+)
+
 type ListUsersResp struct {
 	Total int    `json:"total" example:"100"`
 	Limit int    `json:"limit" example:"20"`
 	Page  int    `json:"page"  example:"1"`
 	Data  []User `json:"data"`
+	// hanya di mode pagination=cursor
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdCJ9.c2ln"`
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdCJ9.c2ln"`
 }
 
 type GetUserResp struct {
@@ -28,3 +37,18 @@ type UpdateUserReq struct {
 type UpdateUserResp struct {
 	Data User `json:"data"`
 }
+
+type UserStatusReq struct {
+	Reason string `json:"reason" example:"Pelanggaran ketentuan layanan"`
+}
+
+// UserStatusResp = user beserta alasan status; hanya untuk endpoint status admin
+type UserStatusResp struct {
+	*domain.User
+	StatusReason string `json:"status_reason,omitempty" example:"Aktivitas mencurigakan"`
+}
+
+type SuspendUserReq struct {
+	Reason string     `json:"reason"          example:"Aktivitas mencurigakan"`
+	Until  *time.Time `json:"until,omitempty" example:"2026-02-01T00:00:00Z"`
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/dto"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type APIKeyHandler struct{ svc service.APIKeyService }
+
+func NewAPIKeyHandler(s service.APIKeyService) *APIKeyHandler { return &APIKeyHandler{svc: s} }
+
+// List godoc
+// @Summary      List API key milik user saat ini
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.ListAPIKeysResp
+// @Failure      401 {object} apperr.AppError
+// @Router       /api/v1/me/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	keys, err := h.svc.List(c.Request.Context(), uid)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	out := dto.ListAPIKeysResp{Data: make([]dto.APIKey, len(keys))}
+	for i := range keys {
+		out.Data[i] = apiKeyResp(&keys[i])
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Create godoc
+// @Summary      Buat API key baru (key hanya ditampilkan sekali)
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.CreateAPIKeyReq true "Nama, scope (opsional), kedaluwarsa (opsional)"
+// @Success      201     {object} dto.CreateAPIKeyResp
+// @Failure      400     {object} apperr.AppError
+// @Failure      403     {object} apperr.AppError
+// @Router       /api/v1/me/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in dto.CreateAPIKeyReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	k, raw, err := h.svc.Create(c.Request.Context(), uid, service.CreateAPIKeyInput{
+		Name:      in.Name,
+		Scopes:    in.Scopes,
+		ExpiresAt: in.ExpiresAt,
+	})
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, dto.CreateAPIKeyResp{APIKey: apiKeyResp(k), Key: raw})
+}
+
+// Revoke godoc
+// @Summary      Cabut API key
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     string true "API key ID (UUID)" format(uuid)
+// @Success      200 {object} map[string]bool
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	if err := h.svc.Revoke(c.Request.Context(), uid, c.Param("id")); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// apiKeyResp: KeyHash dan data internal lain tidak pernah dikirim ke client
+func apiKeyResp(k *domain.APIKey) dto.APIKey {
+	return dto.APIKey{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"crypto/subtle"
+	"errors"
+	"io"
 	"net/http"
 
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
 	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/dto"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -33,12 +38,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
 		return
 	}
-	u, tok, err := h.svc.Register(c.Request.Context(), in.Name, in.Email, in.Password)
+	u, tp, err := h.svc.Register(c.Request.Context(), in.Name, in.Email, in.Password)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"user": u, "token": tok, "token_type": "Bearer"})
+	if tp == nil {
+		// policy verifikasi "login": token baru diberikan setelah email diverifikasi
+		c.JSON(http.StatusCreated, gin.H{"user": u, "message": "cek email untuk verifikasi akun"})
+		return
+	}
+	c.JSON(http.StatusCreated, tokenBody(u, tp))
 }
 
 // Login godoc
@@ -47,8 +57,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        payload body     dto.LoginReq true "Login payload"
-// @Success      200     {object} dto.TokenResp
+// @Success      200     {object} dto.TokenResp "token, atau dto.MFARequiredResp jika 2FA aktif"
 // @Failure      401     {object} apperr.AppError
+// @Failure      403     {object} apperr.AppError "account_suspended / account_disabled"
+// @Failure      423     {object} apperr.AppError "account_locked, lihat header Retry-After"
+// @Failure      429     {object} apperr.AppError "too_many_attempts, lihat header Retry-After"
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var in struct {
@@ -59,12 +72,349 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
 		return
 	}
-	u, tok, err := h.svc.Login(c.Request.Context(), in.Email, in.Password)
+	res, err := h.svc.Login(c.Request.Context(), in.Email, in.Password)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	loginBody(c, res)
+}
+
+// VerifyMFA godoc
+// @Summary      Selesaikan login dengan kode 2FA (TOTP atau recovery code)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.MFAVerifyReq true "MFA payload"
+// @Success      200     {object} dto.TokenResp
+// @Failure      401     {object} apperr.AppError
+// @Router       /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var in struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	u, tp, err := h.svc.VerifyMFA(c.Request.Context(), in.MFAToken, in.Code)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokenBody(u, tp))
+}
+
+// Refresh godoc
+// @Summary      Rotasi refresh token dan dapatkan access token baru
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.RefreshReq true "Refresh payload"
+// @Success      200     {object} dto.TokenResp
+// @Failure      401     {object} apperr.AppError
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var in struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	u, tp, err := h.svc.Refresh(c.Request.Context(), in.RefreshToken)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokenBody(u, tp))
+}
+
+// Logout godoc
+// @Summary      Logout: cabut access token saat ini (dan refresh token jika dikirim)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.LogoutReq false "Logout payload"
+// @Success      200     {object} map[string]bool
+// @Failure      401     {object} apperr.AppError
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	// body opsional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+			return
+		}
+	}
+	if err := h.svc.Logout(c.Request.Context(), uid, c.GetString("token_jti"), c.GetTime("token_exp"), currentSessionID(c), in.RefreshToken); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// LogoutAll godoc
+// @Summary      Logout dari semua perangkat (cabut semua token user)
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]bool
+// @Failure      401 {object} apperr.AppError
+// @Router       /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	if err := h.svc.LogoutAll(c.Request.Context(), uid); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// ForgotPassword godoc
+// @Summary      Minta link reset password via email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.ForgotPasswordReq true "Forgot password payload"
+// @Success      202     {object} dto.MessageResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var in struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.ForgotPassword(c.Request.Context(), in.Email); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "jika email terdaftar, link reset password telah dikirim"})
+}
+
+// RequestMagicLink godoc
+// @Summary      Minta link login tanpa password via email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.MagicLinkReq true "Email"
+// @Success      202     {object} dto.MessageResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var in dto.MagicLinkReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.RequestMagicLink(c.Request.Context(), in.Email); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "jika email terdaftar, link login telah dikirim"})
+}
+
+// VerifyMagicLink godoc
+// @Summary      Tukar token dari link login dengan access token
+// @Tags         auth
+// @Produce      json
+// @Param        token query    string true "Token dari email"
+// @Success      200   {object} dto.TokenResp "token, atau dto.MFARequiredResp jika 2FA aktif"
+// @Failure      400   {object} apperr.AppError
+// @Router       /auth/magic-link/verify [get]
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	res, err := h.svc.MagicLinkLogin(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	loginBody(c, res)
+}
+
+// ResetPassword godoc
+// @Summary      Set password baru menggunakan token reset
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.ResetPasswordReq true "Reset password payload"
+// @Success      200     {object} dto.MessageResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var in struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.ResetPassword(c.Request.Context(), in.Token, in.NewPassword); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "password berhasil diubah, silakan login ulang"})
+}
+
+// VerifyEmail godoc
+// @Summary      Verifikasi email (atau konfirmasi ganti email) menggunakan token dari email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.VerifyEmailReq true "Verify email payload"
+// @Success      200     {object} dto.MessageResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var in struct {
+		Token string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.VerifyEmail(c.Request.Context(), in.Token); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "email berhasil diverifikasi"})
+}
+
+// ResendVerification godoc
+// @Summary      Kirim ulang email verifikasi
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.ResendVerificationReq true "Resend payload"
+// @Success      202     {object} dto.MessageResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var in struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.ResendVerification(c.Request.Context(), in.Email); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "jika email terdaftar dan belum terverifikasi, email verifikasi telah dikirim"})
+}
+
+// OAuthStart godoc
+// @Summary      Mulai login OIDC (authorization code + PKCE)
+// @Description  Redirect (302) ke halaman login provider. Tambahkan mode=json untuk menerima URL-nya saja.
+// @Description  Selalu memasang cookie HttpOnly oauth_state; callback hanya diterima dari browser yang sama.
+// @Tags         auth
+// @Produce      json
+// @Param        provider path  string true  "Nama provider" example(google)
+// @Param        mode     query string false "json = kembalikan authorization_url tanpa redirect"
+// @Success      302
+// @Success      200 {object} dto.OAuthStartResp
+// @Failure      404 {object} apperr.AppError
+// @Router       /auth/oauth/{provider}/start [get]
+func (h *AuthHandler) OAuthStart(c *gin.Context) {
+	provider := c.Param("provider")
+	authURL, state, err := h.svc.OAuthStart(c.Request.Context(), provider)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	setOAuthStateCookie(c, provider, state, int(service.OAuthStateTTL.Seconds()))
+	if c.Query("mode") == "json" {
+		c.JSON(http.StatusOK, gin.H{"authorization_url": authURL})
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OAuthCallback godoc
+// @Summary      Callback OIDC: tukar code dengan token aplikasi
+// @Tags         auth
+// @Produce      json
+// @Param        provider path  string true "Nama provider" example(google)
+// @Param        code     query string true "Authorization code"
+// @Param        state    query string true "State dari /start"
+// @Success      200 {object} dto.TokenResp "token, atau dto.MFARequiredResp jika 2FA aktif"
+// @Failure      400 {object} apperr.AppError "state tidak valid atau tidak cocok dengan cookie oauth_state"
+// @Failure      401 {object} apperr.AppError
+// @Router       /auth/oauth/{provider}/callback [get]
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	// state sekali pakai: cookie dihapus apa pun hasilnya
+	cookie, cookieErr := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, provider, "", -1)
+
+	if e := c.Query("error"); e != "" {
+		response.WriteError(c, apperr.Unauthorized("login dibatalkan provider: "+e, nil))
+		return
+	}
+	// state harus berasal dari /start di browser ini, bukan link dari pihak lain (login CSRF)
+	state := c.Query("state")
+	if cookieErr != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		response.WriteError(c, apperr.BadRequest("state tidak cocok dengan sesi browser, ulangi login", cookieErr))
+		return
+	}
+	res, err := h.svc.OAuthCallback(c.Request.Context(), provider, c.Query("code"), state)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user": u, "token": tok, "token_type": "Bearer"})
+	loginBody(c, res)
+}
+
+const oauthStateCookie = "oauth_state"
+
+// setOAuthStateCookie: cookie hanya dikirim ke /auth/oauth/<provider>; Lax supaya tetap ikut
+// saat redirect balik dari provider. maxAge < 0 = hapus.
+func setOAuthStateCookie(c *gin.Context, provider, state string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, maxAge, "/auth/oauth/"+provider, "", secure, true)
+}
+
+// loginBody menulis token, atau challenge 2FA bila login belum selesai
+func loginBody(c *gin.Context, res *service.LoginResult) {
+	if res.MFA != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    res.MFA.Token,
+			"expires_in":   res.MFA.ExpiresIn,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, tokenBody(res.User, res.Tokens))
+}
+
+// tokenBody menyusun response standar untuk endpoint yang menerbitkan token
+func tokenBody(u *domain.User, tp *service.TokenPair) gin.H {
+	return gin.H{
+		"user":          u,
+		"token":         tp.AccessToken,
+		"token_type":    "Bearer",
+		"refresh_token": tp.RefreshToken,
+		"expires_in":    tp.ExpiresIn,
+	}
 }
 
 // Me godoc
@@ -72,8 +422,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Tags         user
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200 {object} domain.User
-// @Failure      401 {object} apperr.AppError
+// @Param        fields query    string false "sparse fieldset, mis. id,name,email"
+// @Success      200    {object} domain.User
+// @Failure      401    {object} apperr.AppError
 // @Router       /api/v1/users/me [get]
 func (h *UserHandler) Me(c *gin.Context) {
 	uid, ok := c.Get("user_id")
@@ -82,12 +433,13 @@ func (h *UserHandler) Me(c *gin.Context) {
 		return
 	}
 	// reuse Get by ID
-	out, err := h.svc.Get(c.Request.Context(), uid.(uuid.UUID).String())
+	fields := fieldsParam(c)
+	out, err := h.svc.Get(c.Request.Context(), uid.(uuid.UUID).String(), fields)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
-	response.JSON(c, http.StatusOK, out)
+	writeSparse(c, out, fields)
 }
 
 // AdminSetPassword godoc
@@ -120,3 +472,168 @@ func (h *AuthHandler) AdminSetPassword(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+// AdminUnlock godoc
+// @Summary      Buka kunci login user yang terkena lockout (admin only)
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     string true "User ID"
+// @Success      200 {object} map[string]bool
+// @Failure      403 {object} apperr.AppError
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/unlock [post]
+func (h *AuthHandler) AdminUnlock(c *gin.Context) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.WriteError(c, apperr.BadRequest("user id tidak valid", err))
+		return
+	}
+	if err := h.svc.UnlockAccount(c.Request.Context(), uid); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// AdminSuspend godoc
+// @Summary      Suspend user, opsional sampai waktu tertentu (admin only)
+// @Description  Login, refresh dan token yang sudah terbit ditolak dengan kode account_suspended; semua sesi dicabut.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path     string               true "User ID"
+// @Param        payload body     dto.SuspendUserReq   true "Alasan dan batas waktu (opsional)"
+// @Success      200     {object} dto.UserStatusResp
+// @Failure      400     {object} apperr.AppError
+// @Failure      403     {object} apperr.AppError "target punya hak kelola user"
+// @Failure      404     {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/suspend [post]
+func (h *AuthHandler) AdminSuspend(c *gin.Context) {
+	var in dto.SuspendUserReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	h.setStatus(c, service.StatusChange{Status: domain.UserStatusSuspended, Reason: in.Reason, Until: in.Until})
+}
+
+// AdminDisable godoc
+// @Summary      Nonaktifkan user sampai diaktifkan lagi admin (admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path     string             true "User ID"
+// @Param        payload body     dto.UserStatusReq  true "Alasan"
+// @Success      200     {object} dto.UserStatusResp
+// @Failure      400     {object} apperr.AppError
+// @Failure      403     {object} apperr.AppError "target punya hak kelola user"
+// @Failure      404     {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/disable [post]
+func (h *AuthHandler) AdminDisable(c *gin.Context) {
+	var in dto.UserStatusReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	h.setStatus(c, service.StatusChange{Status: domain.UserStatusDisabled, Reason: in.Reason})
+}
+
+// AdminReactivate godoc
+// @Summary      Aktifkan lagi user yang di-suspend / dinonaktifkan (admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path     string             true  "User ID"
+// @Param        payload body     dto.UserStatusReq  false "Catatan (opsional)"
+// @Success      200     {object} dto.UserStatusResp
+// @Failure      404     {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/reactivate [post]
+func (h *AuthHandler) AdminReactivate(c *gin.Context) {
+	var in dto.UserStatusReq
+	if err := c.ShouldBindJSON(&in); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	h.setStatus(c, service.StatusChange{Status: domain.UserStatusActive, Reason: in.Reason})
+}
+
+func (h *AuthHandler) setStatus(c *gin.Context, ch service.StatusChange) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	u, err := h.svc.SetStatus(c.Request.Context(), actorID, c.Param("id"), ch)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, dto.UserStatusResp{User: u, StatusReason: u.StatusReason})
+}
+
+// ChangePassword godoc
+// @Summary      Ganti password sendiri (session lain otomatis logout)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.ChangePasswordReq true "Password lama dan baru"
+// @Success      200     {object} map[string]bool
+// @Failure      400     {object} apperr.AppError
+// @Failure      401     {object} apperr.AppError
+// @Router       /api/v1/users/me/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in dto.ChangePasswordReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.ChangePassword(c.Request.Context(), uid, currentSessionID(c), in.CurrentPassword, in.NewPassword); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// UpdateMe godoc
+// @Summary      Ubah profil sendiri (nama langsung, email setelah verifikasi alamat baru)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.UpdateProfileReq true "Field yang diubah"
+// @Success      200     {object} dto.UpdateProfileResp
+// @Failure      400     {object} apperr.AppError
+// @Failure      409     {object} apperr.AppError
+// @Router       /api/v1/users/me [patch]
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in dto.UpdateProfileReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	res, err := h.svc.UpdateProfile(c.Request.Context(), uid, service.ProfileUpdate{Name: in.Name, Email: in.Email})
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	out := gin.H{"user": res.User}
+	if res.PendingEmail != "" {
+		out["pending_email"] = res.PendingEmail
+	}
+	response.JSON(c, http.StatusOK, out)
+}
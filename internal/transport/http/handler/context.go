@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentUserID mengambil user_id yang di-inject middleware.AuthBearer
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	v, ok := c.Get("user_id")
+	if !ok {
+		return uuid.Nil, false
+	}
+	uid, ok := v.(uuid.UUID)
+	return uid, ok
+}
+
+// currentSessionID mengambil session_id (claim "sid"); uuid.Nil untuk API key / token lama
+func currentSessionID(c *gin.Context) uuid.UUID {
+	v, ok := c.Get("session_id")
+	if !ok {
+		return uuid.Nil
+	}
+	id, _ := v.(uuid.UUID)
+	return id
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/dto"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type ImpersonationHandler struct{ svc service.ImpersonationService }
+
+func NewImpersonationHandler(s service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{svc: s}
+}
+
+// Impersonate godoc
+// @Summary      Login sebagai user lain untuk support (admin only)
+// @Description  Token berumur pendek tanpa refresh token, membawa claim "act" berisi admin. Aksi sensitif (ganti password/email, API key, 2FA, session, route admin) ditolak dan setiap request dicatat di audit log.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path     string             true  "User ID"
+// @Param        payload body     dto.ImpersonateReq false "Alasan (mis. nomor tiket)"
+// @Success      200     {object} dto.ImpersonateResp
+// @Failure      403     {object} apperr.AppError
+// @Failure      404     {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in dto.ImpersonateReq
+	if err := c.ShouldBindJSON(&in); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	out, err := h.svc.Impersonate(c.Request.Context(), actorID, c.Param("id"), in.Reason)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"user":            out.User,
+		"token":           out.AccessToken,
+		"token_type":      "Bearer",
+		"expires_in":      out.ExpiresIn,
+		"impersonator_id": actorID,
+	})
+}
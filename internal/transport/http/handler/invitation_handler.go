@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/dto"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type InvitationHandler struct{ svc service.InvitationService }
+
+func NewInvitationHandler(s service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{svc: s}
+}
+
+// Create godoc
+// @Summary      Undang user baru lewat email (admin only)
+// @Description  Role opsional, butuh permission roles:write. User dibuat saat undangan diterima.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.CreateInvitationReq true "Email, nama (opsional), role (opsional)"
+// @Success      201     {object} dto.Invitation
+// @Failure      400     {object} apperr.AppError
+// @Failure      403     {object} apperr.AppError
+// @Failure      409     {object} apperr.AppError
+// @Router       /api/v1/admin/invitations [post]
+func (h *InvitationHandler) Create(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	var in dto.CreateInvitationReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	out, err := h.svc.Invite(c.Request.Context(), uid, service.InviteInput{Email: in.Email, Name: in.Name, Role: in.Role})
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, out)
+}
+
+// List godoc
+// @Summary      List undangan yang belum diterima (admin only)
+// @Tags         invitations
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.ListInvitationsResp
+// @Failure      403 {object} apperr.AppError
+// @Router       /api/v1/admin/invitations [get]
+func (h *InvitationHandler) List(c *gin.Context) {
+	out, err := h.svc.ListPending(c.Request.Context())
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// Resend godoc
+// @Summary      Kirim ulang undangan dengan token baru (admin only)
+// @Tags         invitations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     string true "Invitation ID (UUID)" format(uuid)
+// @Success      200 {object} dto.Invitation
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/admin/invitations/{id}/resend [post]
+func (h *InvitationHandler) Resend(c *gin.Context) {
+	out, err := h.svc.Resend(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// Revoke godoc
+// @Summary      Cabut undangan yang belum diterima (admin only)
+// @Tags         invitations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     string true "Invitation ID (UUID)" format(uuid)
+// @Success      200 {object} map[string]bool
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/admin/invitations/{id} [delete]
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	if err := h.svc.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// Accept godoc
+// @Summary      Terima undangan: set nama & password lalu akun dibuat
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload body     dto.AcceptInvitationReq true "Token dari email, nama, password"
+// @Success      201     {object} domain.User
+// @Failure      400     {object} apperr.AppError
+// @Failure      409     {object} apperr.AppError
+// @Router       /auth/invitations/accept [post]
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	var in dto.AcceptInvitationReq
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	u, err := h.svc.Accept(c.Request.Context(), in.Token, in.Name, in.Password)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, u)
+}
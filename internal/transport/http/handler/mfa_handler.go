@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type MFAHandler struct{ svc service.MFAService }
+
+func NewMFAHandler(s service.MFAService) *MFAHandler { return &MFAHandler{svc: s} }
+
+// Status godoc
+// @Summary      Status 2FA user saat ini
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.MFAStatusResp
+// @Failure      401 {object} apperr.AppError
+// @Router       /api/v1/me/mfa [get]
+func (h *MFAHandler) Status(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	out, err := h.svc.Status(c.Request.Context(), uid)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// SetupTOTP godoc
+// @Summary      Mulai enrollment TOTP (secret + otpauth URI)
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.TOTPSetupResp
+// @Failure      409 {object} apperr.AppError
+// @Router       /api/v1/me/mfa/totp/setup [post]
+func (h *MFAHandler) SetupTOTP(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	out, err := h.svc.SetupTOTP(c.Request.Context(), uid)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// ConfirmTOTP godoc
+// @Summary      Konfirmasi enrollment TOTP dan dapatkan recovery code
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.MFACodeReq true "Kode dari authenticator"
+// @Success      200     {object} dto.RecoveryCodesResp
+// @Failure      400     {object} apperr.AppError
+// @Router       /api/v1/me/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	uid, code, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+	codes, err := h.svc.ConfirmTOTP(c.Request.Context(), uid, code)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// DisableTOTP godoc
+// @Summary      Nonaktifkan 2FA
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.MFACodeReq true "Kode TOTP atau recovery code"
+// @Success      200     {object} map[string]bool
+// @Failure      401     {object} apperr.AppError
+// @Router       /api/v1/me/mfa/totp [delete]
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	uid, code, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.DisableTOTP(c.Request.Context(), uid, code); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Buat ulang recovery code (code lama tidak berlaku)
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload body     dto.MFACodeReq true "Kode TOTP atau recovery code"
+// @Success      200     {object} dto.RecoveryCodesResp
+// @Failure      401     {object} apperr.AppError
+// @Router       /api/v1/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	uid, code, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+	codes, err := h.svc.RegenerateRecoveryCodes(c.Request.Context(), uid, code)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// bindMFACode mengambil user saat ini + field "code" dari body; menulis error sendiri jika gagal
+func bindMFACode(c *gin.Context) (uuid.UUID, string, bool) {
+	id, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return id, "", false
+	}
+	var in struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return id, "", false
+	}
+	return id, in.Code, true
+}
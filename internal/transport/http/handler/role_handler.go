@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type RoleHandler struct{ svc service.RoleService }
+
+func NewRoleHandler(s service.RoleService) *RoleHandler { return &RoleHandler{svc: s} }
+
+// List godoc
+// @Summary      List role beserta permission-nya
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.ListRolesResp
+// @Failure      403 {object} apperr.AppError
+// @Router       /api/v1/admin/roles [get]
+func (h *RoleHandler) List(c *gin.Context) {
+	out, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// UserRoles godoc
+// @Summary      List role milik user
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID (UUID)" format(uuid)
+// @Success      200 {object} dto.UserRolesResp
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/roles [get]
+func (h *RoleHandler) UserRoles(c *gin.Context) {
+	out, err := h.svc.UserRoles(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// Grant godoc
+// @Summary      Tambahkan role ke user
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path string           true "User ID (UUID)" format(uuid)
+// @Param        payload body dto.GrantRoleReq true "Role payload"
+// @Success      200     {object} map[string]bool
+// @Failure      404     {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/roles [post]
+func (h *RoleHandler) Grant(c *gin.Context) {
+	var in struct {
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
+		return
+	}
+	if err := h.svc.Grant(c.Request.Context(), c.Param("id"), in.Role); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// Revoke godoc
+// @Summary      Cabut role dari user
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "User ID (UUID)" format(uuid)
+// @Param        role path string true "Nama role"
+// @Success      200  {object} map[string]bool
+// @Failure      404  {object} apperr.AppError
+// @Router       /api/v1/admin/users/{id}/roles/{role} [delete]
+func (h *RoleHandler) Revoke(c *gin.Context) {
+	if err := h.svc.Revoke(c.Request.Context(), c.Param("id"), c.Param("role")); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ariyaagustian/gin-boilerplate/internal/service"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
+)
+
+type SessionHandler struct{ svc service.SessionService }
+
+func NewSessionHandler(s service.SessionService) *SessionHandler { return &SessionHandler{svc: s} }
+
+// List godoc
+// @Summary      List session (perangkat) yang sedang login
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.ListSessionsResp
+// @Failure      401 {object} apperr.AppError
+// @Router       /api/v1/me/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	out, err := h.svc.List(c.Request.Context(), uid, currentSessionID(c))
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// Revoke godoc
+// @Summary      Cabut session (logout perangkat tsb)
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     string true "Session ID (UUID)" format(uuid)
+// @Success      200 {object} map[string]bool
+// @Failure      404 {object} apperr.AppError
+// @Router       /api/v1/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		response.WriteError(c, apperr.Unauthorized("unauthorized", nil))
+		return
+	}
+	if err := h.svc.Revoke(c.Request.Context(), uid, c.Param("id")); err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
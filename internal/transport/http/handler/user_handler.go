@@ -3,11 +3,14 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
 	"github.com/ariyaagustian/gin-boilerplate/internal/service"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
+	"github.com/ariyaagustian/gin-boilerplate/pkg/filter"
 	"github.com/ariyaagustian/gin-boilerplate/pkg/response"
 )
 
@@ -17,12 +20,15 @@ func NewUserHandler(s service.UserService) *UserHandler { return &UserHandler{sv
 
 // Create godoc
 // @Summary      Create user
+// @Description  User dibuat tanpa password (hanya bisa login lewat magic link). Untuk akun baru sebaiknya pakai undangan: POST /api/v1/admin/invitations.
 // @Tags         users
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        payload body     dto.CreateUserReq true "User payload"
 // @Success      201     {object} domain.User
+// @Header       201     {string} ETag "versi user"
 // @Failure      400     {object} apperr.AppError
 // @Failure      401     {object} apperr.AppError
 // @Router       /api/v1/users [post]
@@ -40,25 +46,48 @@ func (h *UserHandler) Create(c *gin.Context) {
 		response.WriteError(c, err)
 		return
 	}
+	setETag(c, out)
 	c.JSON(http.StatusCreated, gin.H{"data": out})
 }
 
 // List godoc
 // @Summary      List users
+// @Description  Pencarian q: search_mode=contains (default, substring) atau fuzzy (prefix kata & toleran typo); sort_by=relevance mengurutkan hasil fuzzy berdasarkan kemiripan.
+// @Description  Default pagination offset (page). pagination=cursor memakai keyset pagination: ikuti next_cursor / prev_cursor dari response; total hanya dihitung jika include_total=true.
+// @Description  Filter: filter[field][op]=value, mis. filter[email][ilike]=@example.com, filter[created_at][gte]=2026-01-01, filter[status][in]=active,suspended, filter[email_verified_at][null]=true.
+// @Description  Field: id, name, email, status, created_at, updated_at, email_verified_at, suspended_until. Operator: eq (default), ne, gt, gte, lt, lte, like, ilike, in, nin, null.
 // @Tags         users
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Param        page   query    int false "page"   example(1)
-// @Param        limit  query    int false "limit"  example(20)
-// @Success      200    {array}  dto.ListUsersResp
-// @Failure      401    {object} apperr.AppError
+// @Param        q             query    string false "cari nama / email"
+// @Param        search_mode   query    string false "mode pencarian q" Enums(contains, fuzzy)
+// @Param        sort          query    string false "multi kolom, prefix - = menurun (mis. -created_at,name); menggantikan sort_by / sort_dir"
+// @Param        sort_by       query    string false "kolom sort (relevance butuh q)" Enums(created_at, name, email, relevance)
+// @Param        sort_dir      query    string false "arah sort" Enums(asc, desc)
+// @Param        fields        query    string false "sparse fieldset, mis. id,name,email"
+// @Param        page          query    int    false "page"   example(1)
+// @Param        limit         query    int    false "limit"  example(20)
+// @Param        status        query    string false "status akun" Enums(active, suspended, disabled)
+// @Param        pagination    query    string false "mode pagination" Enums(offset, cursor)
+// @Param        cursor        query    string false "next_cursor / prev_cursor dari response sebelumnya"
+// @Param        include_total query    bool   false "hitung total di mode cursor"
+// @Success      200           {array}  dto.ListUsersResp
+// @Failure      400           {object} apperr.AppError "filter tidak valid, atau cursor tidak valid / tidak cocok dengan sort & filter"
+// @Failure      401           {object} apperr.AppError
 // @Router       /api/v1/users [get]
 func (h *UserHandler) List(c *gin.Context) {
 	// Ambil query params → siapkan default
 	p := service.ListUsersParams{
-		Q:       c.Query("q"),
-		SortBy:  c.DefaultQuery("sort_by", "created_at"),
-		SortDir: c.DefaultQuery("sort_dir", "desc"),
+		Q:          c.Query("q"),
+		SearchMode: c.Query("search_mode"),
+		Status:     c.Query("status"),
+		SortBy:     c.DefaultQuery("sort_by", "created_at"),
+		SortDir:    c.DefaultQuery("sort_dir", "desc"),
+		Sort:       c.Query("sort"),
+		Fields:     fieldsParam(c),
+		Pagination: c.Query("pagination"),
+		Cursor:     c.Query("cursor"),
 	}
 
 	if v := c.Query("page"); v != "" {
@@ -71,47 +100,145 @@ func (h *UserHandler) List(c *gin.Context) {
 			p.PageSize = n
 		}
 	}
+	if v := c.Query("include_total"); v != "" {
+		p.WithTotal, _ = strconv.ParseBool(v)
+	}
+	filters, err := filter.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.WriteError(c, apperr.Validation(err.Error(), err))
+		return
+	}
+	p.Filters = filters
+
+	if p.IsCursor() {
+		out, err := h.svc.ListCursor(c.Request.Context(), p)
+		if err != nil {
+			response.WriteError(c, err)
+			return
+		}
+		if len(p.Fields) == 0 {
+			response.JSON(c, http.StatusOK, out)
+			return
+		}
+		if items, ok := pickItems(c, out.Items, p.Fields); ok {
+			response.JSON(c, http.StatusOK, service.WithCursorItems(out, items))
+		}
+		return
+	}
 
 	out, err := h.svc.List(c.Request.Context(), p)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
-	response.JSON(c, http.StatusOK, out)
+	if len(p.Fields) == 0 {
+		response.JSON(c, http.StatusOK, out)
+		return
+	}
+	if items, ok := pickItems(c, out.Items, p.Fields); ok {
+		response.JSON(c, http.StatusOK, service.WithItems(out, items))
+	}
+}
+
+// pickItems = sparse fieldset untuk setiap item; false = response error sudah ditulis
+func pickItems[T any](c *gin.Context, items []T, fields []string) ([]map[string]any, bool) {
+	out, err := filter.PickEach(items, fields)
+	if err != nil {
+		response.WriteError(c, apperr.Internal("gagal menyusun response", err))
+		return nil, false
+	}
+	return out, true
 }
 
 // Get godoc
 // @Summary      Get user by ID
+// @Description  Header ETag = versi user; kirim kembali lewat If-Match saat update / delete.
 // @Tags         users
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Param        id path string true "User ID (UUID)" format(uuid)
-// @Success      200 {object} domain.User
-// @Failure      404 {object} apperr.AppError
+// @Param        id     path     string true  "User ID (UUID)" format(uuid)
+// @Param        fields query    string false "sparse fieldset, mis. id,name,email"
+// @Success      200    {object} domain.User
+// @Header       200    {string} ETag "versi user"
+// @Failure      400    {object} apperr.AppError "field tidak dikenal"
+// @Failure      404    {object} apperr.AppError
 // @Router       /api/v1/users/{id} [get]
 func (h *UserHandler) Get(c *gin.Context) {
 	id := c.Param("id")
-	out, err := h.svc.Get(c.Request.Context(), id)
+	fields := fieldsParam(c)
+	out, err := h.svc.Get(c.Request.Context(), id, fields)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
+	setETag(c, out)
+	writeSparse(c, out, fields)
+}
+
+// fieldsParam = sparse fieldset dari ?fields=id,name,email
+func fieldsParam(c *gin.Context) []string { return filter.SplitList(c.Query("fields")) }
+
+// writeSparse menulis v hanya dengan field yang diminta; fields kosong = apa adanya
+func writeSparse(c *gin.Context, v any, fields []string) {
+	if len(fields) == 0 {
+		response.JSON(c, http.StatusOK, v)
+		return
+	}
+	out, err := filter.Pick(v, fields)
+	if err != nil {
+		response.WriteError(c, apperr.Internal("gagal menyusun response", err))
+		return
+	}
 	response.JSON(c, http.StatusOK, out)
 }
 
+// setETag memasang ETag dari versi user (lihat domain.User.Version)
+func setETag(c *gin.Context, u *domain.User) {
+	c.Header("ETag", `"`+strconv.FormatInt(u.Version, 10)+`"`)
+}
+
+// ifMatchVersion membaca versi dari header If-Match ("3" atau W/"3").
+// 0 = header kosong atau "*" (tanpa cek versi); wajib-tidaknya header diatur middleware.RequireIfMatch.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	v = strings.TrimPrefix(v, "W/")
+	n, err := strconv.ParseInt(strings.Trim(v, `"`), 10, 64)
+	if err != nil || n <= 0 {
+		// ETag yang tidak dikenal tidak mungkin cocok dengan versi mana pun
+		return 0, apperr.PreconditionFailed("If-Match tidak cocok dengan ETag user", err)
+	}
+	return n, nil
+}
+
 // Update godoc
 // @Summary      Update user
+// @Description  PUT dan PATCH sama: field kosong tidak diubah. If-Match = ETag dari GET; wajib kecuali USER_REQUIRE_IF_MATCH=false.
 // @Tags         users
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
-// @Param        id      path string       true "User ID (UUID)" format(uuid)
-// @Param        payload body dto.UpdateUserReq true "Update payload"
-// @Success      200     {object} domain.User
-// @Failure      400     {object} apperr.AppError
+// @Param        id       path   string            true  "User ID (UUID)" format(uuid)
+// @Param        If-Match header string            false "ETag dari GET user"
+// @Param        payload  body   dto.UpdateUserReq true  "Update payload"
+// @Success      200      {object} domain.User
+// @Header       200      {string} ETag "versi user setelah update"
+// @Failure      400      {object} apperr.AppError
+// @Failure      412      {object} apperr.AppError "user sudah diubah request lain"
+// @Failure      428      {object} apperr.AppError "If-Match wajib"
 // @Router       /api/v1/users/{id} [put]
+// @Router       /api/v1/users/{id} [patch]
 func (h *UserHandler) Update(c *gin.Context) {
 	id := c.Param("id")
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
 	var in struct {
 		Name  string `json:"name"`
 		Email string `json:"email"`
@@ -120,28 +247,80 @@ func (h *UserHandler) Update(c *gin.Context) {
 		response.WriteError(c, apperr.BadRequest("payload tidak valid", err))
 		return
 	}
-	out, err := h.svc.Update(c.Request.Context(), id, in.Name, in.Email)
+	out, err := h.svc.Update(c.Request.Context(), id, in.Name, in.Email, version)
 	if err != nil {
 		response.WriteError(c, err)
 		return
 	}
+	setETag(c, out)
 	response.JSON(c, http.StatusOK, out)
 }
 
 // Delete godoc
 // @Summary      Delete user
+// @Description  Soft delete: user bisa dipulihkan lewat /api/v1/admin/users/{id}/restore sampai masa retensi habis.
 // @Tags         users
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Param        id path string true "User ID (UUID)" format(uuid)
+// @Param        id       path   string true  "User ID (UUID)" format(uuid)
+// @Param        If-Match header string false "ETag user; wajib kecuali USER_REQUIRE_IF_MATCH=false"
 // @Success      204 {string} string "no content"
 // @Failure      404 {object} apperr.AppError
+// @Failure      412 {object} apperr.AppError "user sudah diubah request lain"
+// @Failure      428 {object} apperr.AppError "If-Match wajib"
 // @Router       /api/v1/users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id, version); err != nil {
 		response.WriteError(c, err)
 		return
 	}
 	response.JSON(c, http.StatusOK, gin.H{"deleted": true})
 }
+
+// ListDeleted godoc
+// @Summary      List user yang sudah dihapus (belum di-purge)
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page      query    int false "page"      example(1)
+// @Param        page_size query    int false "page size" example(20)
+// @Success      200       {object} dto.ListUsersResp
+// @Failure      403       {object} apperr.AppError
+// @Router       /api/v1/admin/users/deleted [get]
+func (h *UserHandler) ListDeleted(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	out, err := h.svc.ListDeleted(c.Request.Context(), page, pageSize)
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, out)
+}
+
+// Restore godoc
+// @Summary      Pulihkan user yang sudah dihapus
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path     string true "User ID (UUID)" format(uuid)
+// @Success      200 {object} domain.User
+// @Failure      404 {object} apperr.AppError
+// @Failure      409 {object} apperr.AppError "email sudah dipakai user lain"
+// @Router       /api/v1/admin/users/{id}/restore [post]
+func (h *UserHandler) Restore(c *gin.Context) {
+	out, err := h.svc.Restore(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.WriteError(c, err)
+		return
+	}
+	setETag(c, out)
+	response.JSON(c, http.StatusOK, out)
+}
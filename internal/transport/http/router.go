@@ -5,41 +5,137 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"github.com/ariyaagustian/gin-boilerplate/internal/domain"
 	"github.com/ariyaagustian/gin-boilerplate/internal/middleware"
 	"github.com/ariyaagustian/gin-boilerplate/internal/transport/http/handler"
 	"gorm.io/gorm"
 )
 
+// Handlers = kumpulan handler yang di-mount ke router
+type Handlers struct {
+	User          *handler.UserHandler
+	Auth          *handler.AuthHandler
+	Role          *handler.RoleHandler
+	MFA           *handler.MFAHandler
+	JWKS          *handler.JWKSHandler
+	APIKey        *handler.APIKeyHandler
+	Session       *handler.SessionHandler
+	Impersonation *handler.ImpersonationHandler
+	Invitation    *handler.InvitationHandler
+}
+
+// Middlewares = middleware yang dirakit di main sesuai config
+type Middlewares struct {
+	Auth gin.HandlerFunc
+	// opsional; nil = user dengan email belum terverifikasi tetap boleh akses semua route
+	VerifiedEmail gin.HandlerFunc
+	// opsional; nil = If-Match boleh kosong di PUT/PATCH/DELETE user (tanpa cek versi)
+	IfMatch gin.HandlerFunc
+}
+
+func (m Middlewares) verified() []gin.HandlerFunc {
+	if m.VerifiedEmail == nil {
+		return nil
+	}
+	return []gin.HandlerFunc{m.VerifiedEmail}
+}
+
+func (m Middlewares) ifMatch() gin.HandlerFunc {
+	if m.IfMatch == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return m.IfMatch
+}
+
 // internal/transport/http/router.go
-func NewRouter(userH *handler.UserHandler, authH *handler.AuthHandler, jwtSecret string, db *gorm.DB) *gin.Engine {
+func NewRouter(h Handlers, mw Middlewares, perms middleware.PermissionResolver, db *gorm.DB) *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Recovery(), middleware.Logger(), middleware.CORS())
+	r.Use(gin.Recovery(), middleware.Logger(), middleware.CORS(), middleware.ClientInfo())
+
+	can := func(perm string) gin.HandlerFunc { return middleware.RequirePermission(perms, perm) }
+	// route yang tidak boleh diakses dengan API key
+	userToken := middleware.RequireUserToken()
+	// route yang tidak boleh diakses dengan token impersonation
+	noImpersonation := middleware.BlockImpersonation()
 
 	// Health check endpoints
 	healthH := handler.NewHealthHandler(db)
 	r.GET("/healthz", healthH.HealthCheck)
 	r.GET("/health/liveness", healthH.Liveness)
 	r.GET("/health/readiness", healthH.Readiness)
+	r.GET("/.well-known/jwks.json", h.JWKS.JWKS)
 
-	r.POST("/auth/register", authH.Register)
-	r.POST("/auth/login", authH.Login)
+	r.POST("/auth/register", h.Auth.Register)
+	r.POST("/auth/login", h.Auth.Login)
+	r.POST("/auth/refresh", h.Auth.Refresh)
+	r.POST("/auth/logout", mw.Auth, userToken, h.Auth.Logout)
+	r.POST("/auth/logout-all", mw.Auth, userToken, noImpersonation, h.Auth.LogoutAll)
+	r.POST("/auth/password/forgot", h.Auth.ForgotPassword)
+	r.POST("/auth/password/reset", h.Auth.ResetPassword)
+	r.POST("/auth/magic-link", h.Auth.RequestMagicLink)
+	r.GET("/auth/magic-link/verify", h.Auth.VerifyMagicLink)
+	r.POST("/auth/invitations/accept", h.Invitation.Accept)
+	r.POST("/auth/verify-email", h.Auth.VerifyEmail)
+	r.POST("/auth/verify-email/resend", h.Auth.ResendVerification)
+	r.POST("/auth/mfa/verify", h.Auth.VerifyMFA)
+	r.GET("/auth/oauth/:provider/start", h.Auth.OAuthStart)
+	r.GET("/auth/oauth/:provider/callback", h.Auth.OAuthCallback)
 
-	api := r.Group("/api/v1", middleware.AuthBearer(jwtSecret))
+	api := r.Group("/api/v1", mw.Auth)
 	{
-		// admin only
-		admin := api.Group("/admin", middleware.AdminOnly())
+		// tetap bisa diakses walau email belum terverifikasi
+		api.GET("/users/me", h.User.Me)
+		api.PATCH("/users/me", userToken, noImpersonation, h.Auth.UpdateMe)
+		api.PUT("/users/me/password", userToken, noImpersonation, h.Auth.ChangePassword)
+
+		verified := api.Group("", mw.verified()...)
+
+		admin := verified.Group("/admin", noImpersonation)
+		{
+			admin.POST("/users/set-password", can(domain.PermUsersWrite), h.Auth.AdminSetPassword)
+			admin.POST("/users/:id/unlock", can(domain.PermUsersWrite), h.Auth.AdminUnlock)
+			admin.POST("/users/:id/suspend", can(domain.PermUsersWrite), h.Auth.AdminSuspend)
+			admin.POST("/users/:id/disable", can(domain.PermUsersWrite), h.Auth.AdminDisable)
+			admin.POST("/users/:id/reactivate", can(domain.PermUsersWrite), h.Auth.AdminReactivate)
+			admin.GET("/users/deleted", can(domain.PermUsersRead), h.User.ListDeleted)
+			admin.POST("/users/:id/restore", can(domain.PermUsersDelete), h.User.Restore)
+			admin.POST("/users/:id/impersonate", userToken, can(domain.PermUsersImpersonate), h.Impersonation.Impersonate)
+
+			admin.GET("/invitations", can(domain.PermUsersRead), h.Invitation.List)
+			admin.POST("/invitations", can(domain.PermUsersWrite), h.Invitation.Create)
+			admin.POST("/invitations/:id/resend", can(domain.PermUsersWrite), h.Invitation.Resend)
+			admin.DELETE("/invitations/:id", can(domain.PermUsersWrite), h.Invitation.Revoke)
+
+			admin.GET("/roles", can(domain.PermRolesRead), h.Role.List)
+			admin.GET("/users/:id/roles", can(domain.PermRolesRead), h.Role.UserRoles)
+			admin.POST("/users/:id/roles", can(domain.PermRolesWrite), h.Role.Grant)
+			admin.DELETE("/users/:id/roles/:role", can(domain.PermRolesWrite), h.Role.Revoke)
+		}
+
+		me := verified.Group("/me", userToken, noImpersonation)
 		{
-			admin.POST("/users/set-password", authH.AdminSetPassword)
+			me.GET("/api-keys", h.APIKey.List)
+			me.POST("/api-keys", h.APIKey.Create)
+			me.DELETE("/api-keys/:id", h.APIKey.Revoke)
+
+			me.GET("/sessions", h.Session.List)
+			me.DELETE("/sessions/:id", h.Session.Revoke)
+
+			me.GET("/mfa", h.MFA.Status)
+			me.POST("/mfa/totp/setup", h.MFA.SetupTOTP)
+			me.POST("/mfa/totp/confirm", h.MFA.ConfirmTOTP)
+			me.DELETE("/mfa/totp", h.MFA.DisableTOTP)
+			me.POST("/mfa/recovery-codes", h.MFA.RegenerateRecoveryCodes)
 		}
 
-		u := api.Group("/users")
+		u := verified.Group("/users")
 		{
-			u.POST("", userH.Create)
-			u.GET("", userH.List)
-			u.GET("/:id", userH.Get)
-			u.PUT("/:id", userH.Update)
-			u.DELETE("/:id", userH.Delete)
-			u.GET("/me", userH.Me)
+			u.POST("", can(domain.PermUsersWrite), h.User.Create)
+			u.GET("", can(domain.PermUsersRead), h.User.List)
+			u.GET("/:id", can(domain.PermUsersRead), h.User.Get)
+			u.PUT("/:id", can(domain.PermUsersWrite), mw.ifMatch(), h.User.Update)
+			u.PATCH("/:id", can(domain.PermUsersWrite), mw.ifMatch(), h.User.Update)
+			u.DELETE("/:id", can(domain.PermUsersDelete), mw.ifMatch(), h.User.Delete)
 		}
 	}
 
@@ -3,8 +3,9 @@ package apperr
 import (
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // AppError = error kustom dengan kode, pesan, dan HTTP status
@@ -13,6 +14,8 @@ type AppError struct {
 	Message    string
 	HTTPStatus int
 	Err        error
+	// RetryAfter > 0 → response.WriteError mengirim header Retry-After
+	RetryAfter time.Duration `swaggerignore:"true"`
 }
 
 func (e *AppError) Error() string {
@@ -36,7 +39,43 @@ func Internal(msg string, err error) *AppError     { return New("internal", 500,
 func Unauthorized(msg string, err error) *AppError { return New("unauthorized", 401, msg, err) }
 func Forbidden(msg string, err error) *AppError    { return New("forbidden", 403, msg, err) }
 
+func EmailNotVerified(msg string, err error) *AppError {
+	return New("email_not_verified", 403, msg, err)
+}
+
+func AccountSuspended(msg string) *AppError {
+	return New("account_suspended", 403, msg, nil)
+}
+
+func AccountDisabled(msg string) *AppError {
+	return New("account_disabled", 403, msg, nil)
+}
+
+func AccountLocked(msg string, retryAfter time.Duration) *AppError {
+	e := New("account_locked", 423, msg, nil)
+	e.RetryAfter = retryAfter
+	return e
+}
+
+// PreconditionFailed = If-Match tidak cocok dengan versi data saat ini
+func PreconditionFailed(msg string, err error) *AppError {
+	return New("precondition_failed", 412, msg, err)
+}
+
+// PreconditionRequired = request wajib menyertakan If-Match
+func PreconditionRequired(msg string) *AppError {
+	return New("precondition_required", 428, msg, nil)
+}
+
+func TooManyRequests(msg string, retryAfter time.Duration) *AppError {
+	e := New("too_many_attempts", 429, msg, nil)
+	e.RetryAfter = retryAfter
+	return e
+}
+
 // ---------- Parser khusus Postgres ----------
+// FromPg memetakan *pgconn.PgError (pgx v5, dipakai gorm.io/driver/postgres) ke AppError;
+// nil jika err bukan error dari Postgres.
 func FromPg(err error) *AppError {
 	var pgErr *pgconn.PgError
 	if !errors.As(err, &pgErr) {
@@ -0,0 +1,45 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromPg(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantStatus int
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}, wantCode: "duplicate", wantStatus: 409},
+		{name: "dibungkus", err: fmt.Errorf("create user: %w", &pgconn.PgError{Code: "23505"}), wantCode: "duplicate", wantStatus: 409},
+		{name: "foreign key", err: &pgconn.PgError{Code: "23503"}, wantCode: "bad_request", wantStatus: 400},
+		{name: "kode lain", err: &pgconn.PgError{Code: "42P01", Message: "relation does not exist"}, wantCode: "bad_request", wantStatus: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae := FromPg(tt.err)
+			if ae == nil {
+				t.Fatalf("FromPg(%v) = nil, want %s", tt.err, tt.wantCode)
+			}
+			if ae.Code != tt.wantCode || ae.HTTPStatus != tt.wantStatus {
+				t.Fatalf("FromPg(%v) = %s/%d, want %s/%d", tt.err, ae.Code, ae.HTTPStatus, tt.wantCode, tt.wantStatus)
+			}
+			if !errors.Is(ae, tt.err) {
+				t.Fatalf("FromPg tidak membungkus error asli")
+			}
+		})
+	}
+}
+
+func TestFromPgIgnoresOtherErrors(t *testing.T) {
+	for _, err := range []error{nil, errors.New("duplicate key value violates unique constraint")} {
+		if ae := FromPg(err); ae != nil {
+			t.Fatalf("FromPg(%v) = %v, want nil", err, ae)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,34 +9,125 @@ import (
 )
 
 type Claims struct {
-	UserID string `json:"uid"`
-	Email  string `json:"email,omitempty"`
+	UserID        string   `json:"uid"`
+	Email         string   `json:"email,omitempty"`
+	EmailVerified bool     `json:"email_verified,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	SessionID     string   `json:"sid,omitempty"`
+	// Actor terisi pada token impersonation: admin yang bertindak sebagai user ini (RFC 8693 "act")
+	Actor *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewAccessToken(secret string, uid uuid.UUID, email string, ttl time.Duration) (string, *Claims, error) {
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// Subject = data user yang dimasukkan ke access token
+type Subject struct {
+	UserID        uuid.UUID
+	Email         string
+	EmailVerified bool
+	Roles         []string
+	SessionID     uuid.UUID // uuid.Nil = tanpa claim sid
+	ActorID       uuid.UUID // uuid.Nil = bukan token impersonation
+}
+
+// TokenOptions = claim standar yang dipasang saat sign dan diwajibkan saat verifikasi.
+// Issuer/Audience kosong = tidak dipasang dan tidak dicek.
+type TokenOptions struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration // toleransi selisih jam untuk exp/nbf/iat
+}
+
+// NewAccessToken menandatangani token dengan signing key aktif dari keys (kid ikut di header).
+func NewAccessToken(keys *KeySet, opts TokenOptions, sub Subject, ttl time.Duration) (string, *Claims, error) {
 	now := time.Now()
+	key, err := keys.SigningKey(now)
+	if err != nil {
+		return "", nil, err
+	}
 	claims := &Claims{
-		UserID: uid.String(),
-		Email:  email,
+		UserID:        sub.UserID.String(),
+		Email:         sub.Email,
+		EmailVerified: sub.EmailVerified,
+		Roles:         sub.Roles,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(), // jti, dipakai untuk revoke per token
+			Subject:   sub.UserID.String(),
+			Issuer:    opts.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	s, err := t.SignedString([]byte(secret))
+	if opts.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{opts.Audience}
+	}
+	if sub.SessionID != uuid.Nil {
+		claims.SessionID = sub.SessionID.String()
+	}
+	if sub.ActorID != uuid.Nil {
+		claims.Actor = &Actor{Subject: sub.ActorID.String()}
+	}
+	t := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	t.Header["kid"] = key.ID
+	s, err := t.SignedString(key.signKey)
 	return s, claims, err
 }
 
-func Parse(tokenStr, secret string) (*Claims, error) {
+// Parse memverifikasi token dengan key sesuai kid di header. Hanya algoritma dari key set yang
+// diterima dan algoritma token harus sama dengan algoritma key tsb (mencegah alg confusion,
+// mis. RS256 → HS256). exp, iat, sub dan jti wajib ada; iss/aud dicek jika diset di opts.
+// Error bisa dibedakan dengan errors.Is terhadap jwt.ErrTokenExpired, jwt.ErrTokenInvalidAudience, dst.
+func Parse(tokenStr string, keys *KeySet, opts TokenOptions) (*Claims, error) {
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.Algorithms()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
 	tkn, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		now := time.Now()
+		var key *Key
+		if kid, _ := t.Header["kid"].(string); kid != "" {
+			k, ok := keys.lookup(kid, now)
+			if !ok {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			key = k
+		} else {
+			// token lama tanpa kid: hanya dicoba dengan signing key saat ini
+			k, err := keys.SigningKey(now)
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		}
+		if t.Method.Alg() != key.Algorithm {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return key.verifyKey, nil
+	}, parserOpts...)
 	if err != nil {
 		return nil, err
 	}
 	if c, ok := tkn.Claims.(*Claims); ok && tkn.Valid {
+		if c.Subject == "" || c.ID == "" {
+			return nil, fmt.Errorf("%w: sub dan jti wajib ada", jwt.ErrTokenInvalidClaims)
+		}
+		// WithIssuedAt hanya memvalidasi iat jika ada; cek revocation butuh iat
+		if c.IssuedAt == nil {
+			return nil, fmt.Errorf("%w: iat wajib ada", jwt.ErrTokenInvalidClaims)
+		}
 		return c, nil
 	}
 	return nil, jwt.ErrTokenInvalidClaims
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ariyaagustian/gin-boilerplate/pkg/jwk"
+)
+
+// Key = satu kunci JWT yang diidentifikasi dengan kid.
+// Key tanpa private key (hanya public) dipakai untuk verifikasi saja.
+type Key struct {
+	ID        string // kid
+	Algorithm string // HS256, RS256, ES256, ES384, ES512 atau EdDSA
+	// ActiveFrom: mulai dipakai untuk sign. Sebelum waktu ini key sudah muncul di JWKS
+	// supaya service lain bisa cache lebih dulu.
+	ActiveFrom time.Time
+
+	signKey   any // []byte (HMAC) atau crypto.Signer
+	verifyKey any // []byte (HMAC) atau crypto.PublicKey
+}
+
+var ErrNoSigningKey = errors.New("auth: tidak ada signing key yang aktif")
+
+// NewHMACKey membuat key HS256 dari shared secret.
+func NewHMACKey(kid, secret string) *Key {
+	return &Key{ID: kid, Algorithm: jwt.SigningMethodHS256.Alg(), signKey: []byte(secret), verifyKey: []byte(secret)}
+}
+
+// LoadPEMKey membaca private key (PKCS#8, PKCS#1 atau SEC1) atau public key (PKIX) dari file PEM.
+// Algoritma ditentukan dari tipe key: RSA → RS256, EC → ES256/ES384/ES512, Ed25519 → EdDSA.
+func LoadPEMKey(kid, path string) (*Key, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePEMKey(kid, raw)
+}
+
+func ParsePEMKey(kid string, raw []byte) (*Key, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("auth: key %s bukan PEM", kid)
+	}
+
+	k := &Key{ID: kid}
+	switch block.Type {
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("auth: key %s: %w", kid, err)
+		}
+		k.verifyKey = pub
+	default:
+		priv, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("auth: key %s: %w", kid, err)
+		}
+		k.signKey = priv
+		k.verifyKey = priv.Public()
+	}
+
+	alg, err := algorithmFor(k.verifyKey)
+	if err != nil {
+		return nil, fmt.Errorf("auth: key %s: %w", kid, err)
+	}
+	k.Algorithm = alg
+	return k, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("tipe private key tidak didukung: %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("tipe PEM tidak didukung: %s", block.Type)
+	}
+}
+
+func algorithmFor(pub crypto.PublicKey) (string, error) {
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256.Alg(), nil
+	case *ecdsa.PublicKey:
+		switch p.Curve.Params().Name {
+		case "P-256":
+			return jwt.SigningMethodES256.Alg(), nil
+		case "P-384":
+			return jwt.SigningMethodES384.Alg(), nil
+		case "P-521":
+			return jwt.SigningMethodES512.Alg(), nil
+		}
+		return "", fmt.Errorf("curve tidak didukung: %s", p.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	default:
+		return "", fmt.Errorf("tipe public key tidak didukung: %T", pub)
+	}
+}
+
+// CanSign: key punya private key / secret
+func (k *Key) CanSign() bool { return k.signKey != nil }
+
+// KeySet menyimpan beberapa key untuk rotasi. Key yang sedang dipakai sign adalah key
+// dengan ActiveFrom terbaru yang sudah lewat. Key lama tetap diterima untuk verifikasi
+// selama grace period setelah digantikan (minimal sepanjang umur access token).
+type KeySet struct {
+	keys  []*Key // urut ActiveFrom naik
+	grace time.Duration
+}
+
+func NewKeySet(grace time.Duration, keys ...*Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("auth: key set kosong")
+	}
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, errors.New("auth: kid wajib diisi")
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("auth: kid duplikat: %s", k.ID)
+		}
+		seen[k.ID] = true
+	}
+	sorted := append([]*Key(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom) })
+	return &KeySet{keys: sorted, grace: grace}, nil
+}
+
+// SigningKey = key yang dipakai sign token pada waktu now
+func (s *KeySet) SigningKey(now time.Time) (*Key, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		k := s.keys[i]
+		if k.CanSign() && !now.Before(k.ActiveFrom) {
+			return k, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// VerificationKeys = key yang tokennya masih diterima pada waktu now:
+// signing key saat ini, key terjadwal berikutnya, dan key lama yang belum lewat grace period.
+func (s *KeySet) VerificationKeys(now time.Time) []*Key {
+	current, err := s.SigningKey(now)
+	if err != nil {
+		return nil
+	}
+	var out []*Key
+	for i, k := range s.keys {
+		if k == current || k.ActiveFrom.After(now) {
+			out = append(out, k)
+			continue
+		}
+		// key lama: berhenti diterima grace setelah key berikutnya aktif
+		if i+1 < len(s.keys) && s.keys[i+1].ActiveFrom.Add(s.grace).After(now) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Algorithms = daftar algoritma yang dipakai key di set ini (untuk pinning saat parse)
+func (s *KeySet) Algorithms() []string {
+	var out []string
+	for _, k := range s.keys {
+		if !slices.Contains(out, k.Algorithm) {
+			out = append(out, k.Algorithm)
+		}
+	}
+	return out
+}
+
+func (s *KeySet) lookup(kid string, now time.Time) (*Key, bool) {
+	for _, k := range s.VerificationKeys(now) {
+		if k.ID == kid {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// JWKS mengembalikan public key yang masih berlaku (key HMAC tidak pernah dipublikasikan).
+func (s *KeySet) JWKS(now time.Time) (jwk.Set, error) {
+	set := jwk.Set{Keys: []jwk.Key{}}
+	for _, k := range s.VerificationKeys(now) {
+		if _, secret := k.verifyKey.([]byte); secret {
+			continue
+		}
+		j, err := jwk.FromPublicKey(k.verifyKey, k.ID, k.Algorithm)
+		if err != nil {
+			return jwk.Set{}, err
+		}
+		set.Keys = append(set.Keys, j)
+	}
+	return set, nil
+}
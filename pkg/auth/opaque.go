@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewOpaqueToken membuat token acak (base64url, tanpa padding) sepanjang n byte entropi.
+// Dipakai untuk refresh token dan token sekali pakai lain yang disimpan dalam bentuk hash.
+func NewOpaqueToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken mengembalikan SHA-256 (hex) dari token; hanya hash ini yang disimpan di DB.
+func HashToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+// Package clientinfo membawa informasi client HTTP (IP, user agent) lewat context
+// supaya service tidak perlu bergantung pada gin.
+package clientinfo
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+type Info struct {
+	IP        string
+	UserAgent string
+}
+
+type ctxKey struct{}
+
+func With(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, ctxKey{}, info)
+}
+
+// From mengembalikan Info kosong jika tidak ada di context
+func From(ctx context.Context) Info {
+	info, _ := ctx.Value(ctxKey{}).(Info)
+	return info
+}
+
+// Truncate menyiapkan nilai dari client (user agent, path) untuk kolom teks: byte UTF-8 yang
+// tidak valid dibuang lalu dipotong maksimal n byte tanpa memotong di tengah karakter.
+func Truncate(s string, n int) string {
+	s = strings.ToValidUTF8(s, "")
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,59 @@
+// Package cursor membuat cursor pagination yang opaque dan ditandatangani HMAC-SHA256
+// supaya client tidak bisa merakit atau mengubah posisi sendiri.
+// Hasil berupa base64url(json) + "." + base64url(mac).
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("cursor: tidak valid")
+
+type Codec struct{ key []byte }
+
+// New menurunkan key HMAC dari string konfigurasi; key dipisahkan dari pemakaian lain
+// secret yang sama (mis. JWT_SECRET) lewat prefix.
+func New(secret string) *Codec {
+	sum := sha256.Sum256([]byte("pagination-cursor:" + secret))
+	return &Codec{key: sum[:]}
+}
+
+func (c *Codec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+func (c *Codec) Decode(s string, v any) error {
+	p, sig, ok := strings.Cut(s, ".")
+	if !ok {
+		return ErrInvalid
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(p)
+	if err != nil {
+		return ErrInvalid
+	}
+	mac, err := enc.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	m := hmac.New(sha256.New, c.key)
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -0,0 +1,69 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type pos struct {
+	Values []string `json:"v"`
+	ID     string   `json:"id"`
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	c := New("secret")
+	want := pos{Values: []string{"2024-01-02T03:04:05.123456Z", "Budi"}, ID: "0b5d1c1e-6f0a-4c59-9a43-5c1f1f0f0b11"}
+	s, err := c.Encode(want)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if strings.ContainsAny(s, "+/=") {
+		t.Fatalf("Encode = %q, want base64url tanpa padding", s)
+	}
+	var got pos
+	if err := c.Decode(s, &got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.ID != want.ID || strings.Join(got.Values, "|") != strings.Join(want.Values, "|") {
+		t.Fatalf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+func TestCodecRejectsTampered(t *testing.T) {
+	c := New("secret")
+	valid, err := c.Encode(pos{Values: []string{"a"}, ID: "1"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	payload, sig, _ := strings.Cut(valid, ".")
+	enc := base64.RawURLEncoding
+	forged := enc.EncodeToString([]byte(`{"v":["z"],"id":"1"}`))
+	otherKey, _ := New("secret-lain").Encode(pos{Values: []string{"a"}, ID: "1"})
+	notJSON := enc.EncodeToString([]byte("bukan json"))
+	notJSONSig := enc.EncodeToString(c.sign([]byte("bukan json")))
+
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{name: "kosong", s: ""},
+		{name: "tanpa tanda tangan", s: payload},
+		{name: "payload diganti", s: forged + "." + sig},
+		{name: "tanda tangan diganti", s: payload + "." + enc.EncodeToString([]byte("xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"))},
+		{name: "tanda tangan dipotong", s: payload + "." + sig[:len(sig)-2]},
+		{name: "payload bukan base64", s: "!!." + sig},
+		{name: "tanda tangan bukan base64", s: payload + ".!!"},
+		{name: "key berbeda", s: otherKey},
+		{name: "payload bertanda tangan tapi bukan json", s: notJSON + "." + notJSONSig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got pos
+			if err := c.Decode(tt.s, &got); !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Decode(%q) = %v, want ErrInvalid", tt.s, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// ParseFields memvalidasi daftar field (sparse fieldset, fields=id,name) terhadap allowed
+// (nama field JSON → kolom) dan mengembalikan nama field unik sesuai urutan.
+func ParseFields(names []string, allowed map[string]string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if _, ok := allowed[n]; !ok {
+			return nil, &Error{Key: "fields", Msg: "field tidak dikenal: " + n}
+		}
+		if !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// Pick mengubah v (struct) menjadi map JSON yang hanya berisi fields plus "id"
+func Pick(v any, fields []string) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	out := make(map[string]any, len(fields)+1)
+	for _, f := range append(slices.Clip(fields), "id") {
+		if val, ok := m[f]; ok {
+			out[f] = val
+		}
+	}
+	return out, nil
+}
+
+// PickEach = Pick untuk setiap item
+func PickEach[T any](items []T, fields []string) ([]map[string]any, error) {
+	out := make([]map[string]any, len(items))
+	for i := range items {
+		m, err := Pick(items[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = m
+	}
+	return out, nil
+}
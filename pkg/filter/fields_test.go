@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	allowed := map[string]string{"id": "id", "name": "name", "email": "email"}
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "kosong", names: nil, want: []string{}},
+		{name: "urutan dipertahankan", names: []string{"email", "name"}, want: []string{"email", "name"}},
+		{name: "duplikat dibuang", names: []string{"name", "email", "name"}, want: []string{"name", "email"}},
+		{name: "field tidak dikenal", names: []string{"name", "password_hash"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFields(tt.names, allowed)
+			if tt.wantErr {
+				var fe *Error
+				if !errors.As(err, &fe) || fe.Key != "fields" {
+					t.Fatalf("ParseFields error = %v, want *Error key fields", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFields: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("ParseFields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickEach(t *testing.T) {
+	type user struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Email  string `json:"email"`
+		Secret string `json:"-"`
+	}
+	items := []user{{ID: "1", Name: "Budi", Email: "budi@example.com", Secret: "x"}}
+	tests := []struct {
+		name   string
+		fields []string
+		want   map[string]any
+	}{
+		{name: "id selalu ikut", fields: []string{"name"}, want: map[string]any{"id": "1", "name": "Budi"}},
+		{name: "field tersembunyi tidak ikut", fields: []string{"email", "Secret"}, want: map[string]any{"id": "1", "email": "budi@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PickEach(items, tt.fields)
+			if err != nil {
+				t.Fatalf("PickEach: %v", err)
+			}
+			if len(got) != 1 || !maps.Equal(got[0], tt.want) {
+				t.Fatalf("PickEach = %v, want [%v]", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Package filter mem-parse query string filter[field][op]=value menjadi kondisi, memvalidasinya
+// terhadap whitelist field per resource (Schema), lalu menerjemahkannya menjadi scope GORM.
+// Nama kolom hanya diambil dari Schema; nilai dari client selalu lewat placeholder.
+package filter
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Op string
+
+const (
+	Eq    Op = "eq"
+	Ne    Op = "ne"
+	Gt    Op = "gt"
+	Gte   Op = "gte"
+	Lt    Op = "lt"
+	Lte   Op = "lte"
+	Like  Op = "like"  // mengandung, case-sensitive
+	ILike Op = "ilike" // mengandung, case-insensitive
+	In    Op = "in"    // daftar dipisah koma
+	NotIn Op = "nin"
+	Null  Op = "null" // true = IS NULL, false = IS NOT NULL
+)
+
+const (
+	MaxConditions = 20
+	MaxValues     = 50 // jumlah nilai in / nin
+	maxValueLen   = 200
+)
+
+// Kind menentukan cara nilai di-parse dan operator default
+type Kind int
+
+const (
+	String Kind = iota
+	Time        // RFC3339 atau YYYY-MM-DD
+	UUID
+)
+
+// Field = satu field yang boleh difilter
+type Field struct {
+	Column   string
+	Kind     Kind
+	Ops      []Op     // nil = default sesuai Kind (lihat Field.ops)
+	Enum     []string // opsional, nilai String yang diizinkan
+	Nullable bool     // mengizinkan operator null
+	// Expr opsional, dipakai menggantikan Column untuk nilai turunan (mis. CASE ... END)
+	Expr *clause.Expr
+}
+
+// Schema = whitelist field per resource, key = nama di query string
+type Schema map[string]Field
+
+type Condition struct {
+	Field string
+	Op    Op
+	Value string
+}
+
+func (c Condition) key() string { return "filter[" + c.Field + "][" + string(c.Op) + "]" }
+
+// Error = parameter filter / sort / fields dari client tidak valid
+type Error struct {
+	Key string
+	Msg string
+}
+
+func (e *Error) Error() string { return e.Key + ": " + e.Msg }
+
+var keyRe = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// Parse mengambil semua parameter filter[...] dari q; filter[field]=v sama dengan
+// filter[field][eq]=v. Hasil diurutkan supaya stabil (lihat Canonical).
+func Parse(q url.Values) ([]Condition, error) {
+	var out []Condition
+	for key, vals := range q {
+		if !strings.HasPrefix(key, "filter[") {
+			continue
+		}
+		m := keyRe.FindStringSubmatch(key)
+		if m == nil {
+			return nil, &Error{Key: key, Msg: "format harus filter[field][operator]"}
+		}
+		op := Op(m[2])
+		if op == "" {
+			op = Eq
+		}
+		for _, v := range vals {
+			out = append(out, Condition{Field: m[1], Op: op, Value: v})
+		}
+	}
+	if len(out) > MaxConditions {
+		return nil, &Error{Key: "filter", Msg: fmt.Sprintf("maksimal %d kondisi", MaxConditions)}
+	}
+	slices.SortFunc(out, func(a, b Condition) int {
+		return cmp.Or(cmp.Compare(a.Field, b.Field), cmp.Compare(a.Op, b.Op), cmp.Compare(a.Value, b.Value))
+	})
+	return out, nil
+}
+
+// Canonical = bentuk kanonik kondisi hasil Parse, mis. untuk memastikan cursor dipakai dengan filter yang sama
+func Canonical(conds []Condition) string {
+	v := url.Values{}
+	for _, c := range conds {
+		v.Add(c.key(), c.Value)
+	}
+	return v.Encode()
+}
+
+// Scope menerjemahkan kondisi menjadi WHERE yang digabung dengan AND. Field, operator atau
+// nilai yang tidak diizinkan Schema menghasilkan *Error.
+func (s Schema) Scope(conds []Condition) (func(*gorm.DB) *gorm.DB, error) {
+	exprs := make([]clause.Expression, 0, len(conds))
+	for _, c := range conds {
+		e, err := s.expr(c)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		for _, e := range exprs {
+			db = db.Where(e)
+		}
+		return db
+	}, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s Schema) expr(c Condition) (clause.Expression, error) {
+	f, ok := s[c.Field]
+	if !ok {
+		return nil, &Error{Key: c.key(), Msg: "field tidak dikenal (" + s.names() + ")"}
+	}
+	if ops := f.ops(); !slices.Contains(ops, c.Op) {
+		return nil, &Error{Key: c.key(), Msg: "operator tidak didukung (" + joinOps(ops) + ")"}
+	}
+	var col any = clause.Column{Name: f.Column}
+	if f.Expr != nil {
+		col = *f.Expr
+	}
+
+	switch c.Op {
+	case Null:
+		isNull, err := strconv.ParseBool(c.Value)
+		if err != nil {
+			return nil, &Error{Key: c.key(), Msg: "nilai harus true atau false"}
+		}
+		if isNull {
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{col}}, nil
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{col}}, nil
+	case Like, ILike:
+		if len(c.Value) > maxValueLen {
+			return nil, &Error{Key: c.key(), Msg: fmt.Sprintf("nilai maksimal %d karakter", maxValueLen)}
+		}
+		pattern := "%" + likeEscaper.Replace(c.Value) + "%"
+		if c.Op == ILike {
+			return clause.Expr{SQL: `LOWER(?) LIKE LOWER(?) ESCAPE '\'`, Vars: []any{col, pattern}}, nil
+		}
+		return clause.Expr{SQL: `? LIKE ? ESCAPE '\'`, Vars: []any{col, pattern}}, nil
+	case In, NotIn:
+		parts := strings.Split(c.Value, ",")
+		if len(parts) > MaxValues {
+			return nil, &Error{Key: c.key(), Msg: fmt.Sprintf("maksimal %d nilai", MaxValues)}
+		}
+		vals := make([]any, 0, len(parts))
+		for _, p := range parts {
+			v, msg := f.value(strings.TrimSpace(p))
+			if msg != "" {
+				return nil, &Error{Key: c.key(), Msg: msg}
+			}
+			vals = append(vals, v)
+		}
+		if c.Op == In {
+			return clause.IN{Column: col, Values: vals}, nil
+		}
+		return clause.Not(clause.IN{Column: col, Values: vals}), nil
+	}
+
+	v, msg := f.value(c.Value)
+	if msg != "" {
+		return nil, &Error{Key: c.key(), Msg: msg}
+	}
+	switch c.Op {
+	case Ne:
+		return clause.Neq{Column: col, Value: v}, nil
+	case Gt:
+		return clause.Gt{Column: col, Value: v}, nil
+	case Gte:
+		return clause.Gte{Column: col, Value: v}, nil
+	case Lt:
+		return clause.Lt{Column: col, Value: v}, nil
+	case Lte:
+		return clause.Lte{Column: col, Value: v}, nil
+	default:
+		return clause.Eq{Column: col, Value: v}, nil
+	}
+}
+
+// value mem-parse nilai sesuai Kind; msg != "" = nilai tidak valid
+func (f Field) value(raw string) (v any, msg string) {
+	switch f.Kind {
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, ""
+		}
+		if t, err := time.Parse(time.DateOnly, raw); err == nil {
+			return t, ""
+		}
+		return nil, "format waktu harus RFC3339 atau YYYY-MM-DD"
+	case UUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, "uuid tidak valid"
+		}
+		return id, ""
+	}
+	if len(f.Enum) > 0 && !slices.Contains(f.Enum, raw) {
+		return nil, "nilai harus salah satu dari " + strings.Join(f.Enum, ", ")
+	}
+	if len(raw) > maxValueLen {
+		return nil, fmt.Sprintf("nilai maksimal %d karakter", maxValueLen)
+	}
+	return raw, ""
+}
+
+func (f Field) ops() []Op {
+	var ops []Op
+	switch {
+	case f.Ops != nil:
+		ops = f.Ops
+	case f.Kind == Time:
+		ops = []Op{Eq, Ne, Gt, Gte, Lt, Lte}
+	case f.Kind == UUID, len(f.Enum) > 0:
+		ops = []Op{Eq, Ne, In, NotIn}
+	default:
+		ops = []Op{Eq, Ne, Like, ILike, In, NotIn}
+	}
+	if f.Nullable {
+		ops = append(slices.Clip(ops), Null)
+	}
+	return ops
+}
+
+func (s Schema) names() string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+func joinOps(ops []Op) string {
+	out := make([]string, len(ops))
+	for i, op := range ops {
+		out[i] = string(op)
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,187 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var testSchema = Schema{
+	"name":       {Column: "name"},
+	"status":     {Column: "status", Enum: []string{"active", "suspended"}},
+	"created_at": {Column: "created_at", Kind: Time},
+	"id":         {Column: "id", Kind: UUID},
+	"deleted_at": {Column: "deleted_at", Kind: Time, Nullable: true},
+}
+
+type row struct {
+	ID string
+}
+
+// dryRun membangun SQL tanpa koneksi database
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	return db
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []Condition
+		wantErr string
+	}{
+		{name: "tanpa filter", query: "page=1&sort=name"},
+		{
+			name:  "operator default eq",
+			query: "filter[name]=budi",
+			want:  []Condition{{Field: "name", Op: Eq, Value: "budi"}},
+		},
+		{
+			name:  "diurutkan stabil",
+			query: "filter[status][in]=active&filter[name][ilike]=b&filter[name][eq]=a",
+			want: []Condition{
+				{Field: "name", Op: Eq, Value: "a"},
+				{Field: "name", Op: ILike, Value: "b"},
+				{Field: "status", Op: In, Value: "active"},
+			},
+		},
+		{name: "format salah", query: "filter[name][eq][x]=a", wantErr: "filter[name][eq][x]"},
+		{name: "huruf besar", query: "filter[Name]=a", wantErr: "filter[Name]"},
+		{name: "terlalu banyak kondisi", query: strings.Repeat("filter[name]=a&", MaxConditions+1), wantErr: "filter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(q)
+			if tt.wantErr != "" {
+				var fe *Error
+				if !errors.As(err, &fe) || fe.Key != tt.wantErr {
+					t.Fatalf("Parse error = %v, want *Error key %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	a, _ := Parse(url.Values{"filter[status]": {"active"}, "filter[name][ilike]": {"b"}})
+	b, _ := Parse(url.Values{"filter[name][ilike]": {"b"}, "filter[status][eq]": {"active"}})
+	if Canonical(a) != Canonical(b) {
+		t.Fatalf("Canonical berbeda: %q vs %q", Canonical(a), Canonical(b))
+	}
+}
+
+func TestSchemaScopeRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    Condition
+		wantKey string
+		wantMsg string
+	}{
+		{name: "field tidak dikenal", cond: Condition{Field: "password", Op: Eq, Value: "x"}, wantKey: "filter[password][eq]", wantMsg: "field tidak dikenal"},
+		{name: "operator tidak dikenal", cond: Condition{Field: "name", Op: "regex", Value: "x"}, wantKey: "filter[name][regex]", wantMsg: "operator tidak didukung"},
+		{name: "operator tidak cocok kind", cond: Condition{Field: "created_at", Op: Like, Value: "2024"}, wantKey: "filter[created_at][like]", wantMsg: "operator tidak didukung"},
+		{name: "null tanpa nullable", cond: Condition{Field: "name", Op: Null, Value: "true"}, wantKey: "filter[name][null]", wantMsg: "operator tidak didukung"},
+		{name: "null bukan boolean", cond: Condition{Field: "deleted_at", Op: Null, Value: "ya"}, wantKey: "filter[deleted_at][null]", wantMsg: "true atau false"},
+		{name: "enum salah", cond: Condition{Field: "status", Op: Eq, Value: "banned"}, wantKey: "filter[status][eq]", wantMsg: "salah satu dari"},
+		{name: "waktu salah", cond: Condition{Field: "created_at", Op: Gt, Value: "kemarin"}, wantKey: "filter[created_at][gt]", wantMsg: "format waktu"},
+		{name: "uuid salah", cond: Condition{Field: "id", Op: Eq, Value: "123"}, wantKey: "filter[id][eq]", wantMsg: "uuid"},
+		{name: "in terlalu banyak", cond: Condition{Field: "name", Op: In, Value: strings.Repeat("a,", MaxValues+1)}, wantKey: "filter[name][in]", wantMsg: "maksimal"},
+		{name: "nilai terlalu panjang", cond: Condition{Field: "name", Op: Eq, Value: strings.Repeat("a", maxValueLen+1)}, wantKey: "filter[name][eq]", wantMsg: "maksimal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testSchema.Scope([]Condition{tt.cond})
+			var fe *Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Scope error = %v, want *Error", err)
+			}
+			if fe.Key != tt.wantKey || !strings.Contains(fe.Msg, tt.wantMsg) {
+				t.Fatalf("Scope error = %q: %q, want %q: ...%q...", fe.Key, fe.Msg, tt.wantKey, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestSchemaScopeSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		conds    []Condition
+		wantSQL  string
+		wantVars int
+	}{
+		{
+			name:     "ilike dan eq",
+			conds:    []Condition{{Field: "name", Op: ILike, Value: `50%_off\`}, {Field: "status", Op: Eq, Value: "active"}},
+			wantSQL:  `SELECT * FROM "rows" WHERE LOWER("name") LIKE LOWER($1) ESCAPE '\' AND "status" = $2`,
+			wantVars: 2,
+		},
+		{
+			name:     "in",
+			conds:    []Condition{{Field: "status", Op: In, Value: "active, suspended"}},
+			wantSQL:  `SELECT * FROM "rows" WHERE "status" IN ($1,$2)`,
+			wantVars: 2,
+		},
+		{
+			name:    "null true",
+			conds:   []Condition{{Field: "deleted_at", Op: Null, Value: "true"}},
+			wantSQL: `SELECT * FROM "rows" WHERE "deleted_at" IS NULL`,
+		},
+		{
+			name:     "rentang waktu",
+			conds:    []Condition{{Field: "created_at", Op: Gte, Value: "2024-01-01"}, {Field: "created_at", Op: Lt, Value: "2024-02-01T00:00:00Z"}},
+			wantSQL:  `SELECT * FROM "rows" WHERE "created_at" >= $1 AND "created_at" < $2`,
+			wantVars: 2,
+		},
+	}
+	db := dryRun(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := testSchema.Scope(tt.conds)
+			if err != nil {
+				t.Fatalf("Scope: %v", err)
+			}
+			stmt := db.Scopes(scope).Find(&[]row{}).Statement
+			if got := stmt.SQL.String(); got != tt.wantSQL {
+				t.Fatalf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			if len(stmt.Vars) != tt.wantVars {
+				t.Fatalf("vars = %v, want %d nilai", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestSchemaScopeEscapesLike(t *testing.T) {
+	scope, err := testSchema.Scope([]Condition{{Field: "name", Op: Like, Value: `50%_off\`}})
+	if err != nil {
+		t.Fatalf("Scope: %v", err)
+	}
+	stmt := dryRun(t).Scopes(scope).Find(&[]row{}).Statement
+	if len(stmt.Vars) != 1 || stmt.Vars[0] != `%50\%\_off\\%` {
+		t.Fatalf("vars = %q, want [%q]", stmt.Vars, `%50\%\_off\\%`)
+	}
+}
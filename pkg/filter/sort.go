@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxSortKeys = jumlah kolom maksimal di parameter sort
+const MaxSortKeys = 3
+
+// SortKey = satu kolom dari parameter sort=-created_at,name ("-" = menurun)
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort mem-parse daftar kolom sort dipisah koma; setiap kolom harus ada di allowed
+// dan tidak boleh diulang.
+func ParseSort(spec string, allowed map[string]struct{}) ([]SortKey, error) {
+	parts := SplitList(spec)
+	if len(parts) > MaxSortKeys {
+		return nil, &Error{Key: "sort", Msg: fmt.Sprintf("maksimal %d kolom", MaxSortKeys)}
+	}
+	keys := make([]SortKey, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+	for _, p := range parts {
+		field, desc := strings.CutPrefix(p, "-")
+		if _, ok := allowed[field]; !ok {
+			return nil, &Error{Key: "sort", Msg: "kolom tidak dikenal: " + field}
+		}
+		if seen[field] {
+			return nil, &Error{Key: "sort", Msg: "kolom diulang: " + field}
+		}
+		seen[field] = true
+		keys = append(keys, SortKey{Field: field, Desc: desc})
+	}
+	return keys, nil
+}
+
+// SortString = kebalikan ParseSort, mis. "-created_at,name"
+func SortString(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.Field
+		if k.Desc {
+			parts[i] = "-" + k.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// SplitList memecah "a, b,,c" menjadi [a b c]
+func SplitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := map[string]struct{}{"name": {}, "email": {}, "created_at": {}, "id": {}}
+	tests := []struct {
+		name    string
+		spec    string
+		want    []SortKey
+		wantErr bool
+	}{
+		{name: "kosong", spec: "", want: []SortKey{}},
+		{name: "satu kolom naik", spec: "name", want: []SortKey{{Field: "name"}}},
+		{name: "campuran arah", spec: "-created_at, name", want: []SortKey{{Field: "created_at", Desc: true}, {Field: "name"}}},
+		{name: "koma kosong diabaikan", spec: "name,,", want: []SortKey{{Field: "name"}}},
+		{name: "kolom tidak dikenal", spec: "password", wantErr: true},
+		{name: "kolom diulang", spec: "name,-name", wantErr: true},
+		{name: "terlalu banyak kolom", spec: "name,email,created_at,id", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.spec, allowed)
+			if tt.wantErr {
+				var fe *Error
+				if !errors.As(err, &fe) || fe.Key != "sort" {
+					t.Fatalf("ParseSort(%q) error = %v, want *Error key sort", tt.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q): %v", tt.spec, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("ParseSort(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+			if s := SortString(got); s != SortString(tt.want) {
+				t.Fatalf("SortString = %q, want %q", s, SortString(tt.want))
+			}
+		})
+	}
+}
+
+func TestSortString(t *testing.T) {
+	got := SortString([]SortKey{{Field: "created_at", Desc: true}, {Field: "name"}})
+	if got != "-created_at,name" {
+		t.Fatalf("SortString = %q, want %q", got, "-created_at,name")
+	}
+}
@@ -0,0 +1,123 @@
+// Package jwk mengonversi public key ke/dari format JSON Web Key (RFC 7517/7518/8037).
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC / OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// FromPublicKey membuat JWK (use=sig) dari *rsa.PublicKey, *ecdsa.PublicKey atau ed25519.PublicKey.
+func FromPublicKey(pub crypto.PublicKey, kid, alg string) (Key, error) {
+	k := Key{Kid: kid, Use: "sig", Alg: alg}
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		k.Kty = "RSA"
+		k.N = b64.EncodeToString(p.N.Bytes())
+		k.E = b64.EncodeToString(big.NewInt(int64(p.E)).Bytes())
+	case *ecdsa.PublicKey:
+		raw, err := p.Bytes() // 0x04 || X || Y
+		if err != nil {
+			return Key{}, err
+		}
+		size := (len(raw) - 1) / 2
+		k.Kty = "EC"
+		k.Crv = p.Curve.Params().Name
+		k.X = b64.EncodeToString(raw[1 : 1+size])
+		k.Y = b64.EncodeToString(raw[1+size:])
+	case ed25519.PublicKey:
+		k.Kty = "OKP"
+		k.Crv = "Ed25519"
+		k.X = b64.EncodeToString(p)
+	default:
+		return Key{}, fmt.Errorf("jwk: tipe key tidak didukung: %T", pub)
+	}
+	return k, nil
+}
+
+// PublicKey mengubah JWK menjadi public key Go.
+func (k Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("jwk: key RSA tidak valid")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwk: curve tidak didukung: %s", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("jwk: panjang koordinat EC tidak valid")
+		}
+		point := append([]byte{0x04}, append(x, y...)...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwk: curve tidak didukung: %s", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk: key Ed25519 tidak valid")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("jwk: kty tidak didukung: %s", k.Kty)
+	}
+}
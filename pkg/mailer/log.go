@@ -0,0 +1,18 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer hanya menulis email ke log; untuk development.
+type LogMailer struct {
+	From string
+}
+
+func NewLogMailer(from string) *LogMailer { return &LogMailer{From: from} }
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	log.Printf("mail from=%s to=%s subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message = email plain text sederhana
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer mengirim email. Implementasi produksi (SMTP/API) cukup memenuhi interface ini.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedChecker mengecek apakah password pernah muncul di kebocoran data
+type BreachedChecker interface {
+	Breached(pw string) (bool, error)
+}
+
+// RangeDir membaca daftar breached offline dengan layout k-anonymity ala HIBP:
+// satu file per 5 karakter awal SHA-1 (mis. "<dir>/5BAA6" atau "<dir>/5BAA6.txt") yang berisi
+// baris "SISA_HASH:JUMLAH". Layout ini sama dengan hasil unduhan range API Pwned Passwords,
+// jadi password tidak pernah dikirim ke pihak luar.
+type RangeDir struct {
+	Dir string
+}
+
+func NewRangeDir(dir string) *RangeDir { return &RangeDir{Dir: dir} }
+
+func (d *RangeDir) Breached(pw string) (bool, error) {
+	sum := sha1.Sum([]byte(pw))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := h[:5], h[5:]
+
+	f, err := d.open(prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil // prefix tidak ada di daftar
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		hashPart, count, _ := strings.Cut(line, ":")
+		if strings.EqualFold(hashPart, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
+
+func (d *RangeDir) open(prefix string) (*os.File, error) {
+	f, err := os.Open(filepath.Join(d.Dir, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(d.Dir, prefix+".txt"))
+	}
+	return f, err
+}
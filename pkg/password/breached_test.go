@@ -0,0 +1,42 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRangeDirBreached(t *testing.T) {
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	// SHA-1("hunter2")  = F3BBBD66A63D4BF1747940578EC3D0103530E21D
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("5BAA6", "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n")
+	write("F3BBB.txt", "d66a63d4bf1747940578ec3d0103530e21d:0\n")
+
+	tests := []struct {
+		name string
+		pw   string
+		want bool
+	}{
+		{name: "ada di file prefix", pw: "password", want: true},
+		{name: "file .txt, jumlah 0 tidak dihitung", pw: "hunter2", want: false},
+		{name: "prefix tidak ada", pw: "Kx9#vTq2!mWz", want: false},
+	}
+	d := NewRangeDir(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.Breached(tt.pw)
+			if err != nil {
+				t.Fatalf("Breached(%q) error: %v", tt.pw, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Breached(%q) = %v, want %v", tt.pw, got, tt.want)
+			}
+		})
+	}
+}
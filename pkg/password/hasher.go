@@ -0,0 +1,168 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+)
+
+// BcryptMaxBytes = batas panjang input bcrypt; password yang lebih panjang ditolak Hash
+// (bukan dipotong), jadi pasang juga sebagai Policy.MaxBytes jika memakai bcrypt
+const BcryptMaxBytes = 72
+
+var (
+	ErrMismatch      = errors.New("password: password tidak cocok")
+	ErrUnknownFormat = errors.New("password: format hash tidak dikenali")
+)
+
+// Hasher membuat dan memverifikasi hash password.
+// Verify mengembalikan needsRehash=true jika hash cocok tapi dibuat dengan algoritma / parameter
+// yang berbeda dari konfigurasi sekarang, supaya pemanggil bisa menyimpan hash baru.
+type Hasher interface {
+	Hash(pw string) (string, error)
+	Verify(pw, encoded string) (needsRehash bool, err error)
+}
+
+// Argon2Params = parameter argon2id. Memory dalam KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params mengikuti rekomendasi OWASP (m=64MiB, t=3, p=2)
+var DefaultArgon2Params = Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+// HasherConfig: Algorithm menentukan format hash baru; hash lama dengan algoritma lain tetap bisa diverifikasi.
+type HasherConfig struct {
+	Algorithm  string // argon2id (default) atau bcrypt
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+type hasher struct {
+	cfg HasherConfig
+}
+
+func NewHasher(cfg HasherConfig) (Hasher, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = AlgArgon2id
+	}
+	if cfg.Argon2 == (Argon2Params{}) {
+		cfg.Argon2 = DefaultArgon2Params
+	}
+	if cfg.BcryptCost == 0 {
+		cfg.BcryptCost = bcrypt.DefaultCost
+	}
+	switch cfg.Algorithm {
+	case AlgArgon2id, AlgBcrypt:
+	default:
+		return nil, fmt.Errorf("password: algoritma hash tidak didukung: %s", cfg.Algorithm)
+	}
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("password: bcrypt cost harus %d..%d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	a := cfg.Argon2
+	if a.Memory < 8*uint32(a.Parallelism) || a.Iterations < 1 || a.Parallelism < 1 || a.SaltLength < 8 || a.KeyLength < 16 {
+		return nil, errors.New("password: parameter argon2id tidak valid")
+	}
+	return &hasher{cfg: cfg}, nil
+}
+
+func (h *hasher) Hash(pw string) (string, error) {
+	if h.cfg.Algorithm == AlgBcrypt {
+		b, err := bcrypt.GenerateFromPassword([]byte(pw), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	p := h.cfg.Argon2
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(pw), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return encodeArgon2(p, salt, key), nil
+}
+
+func (h *hasher) Verify(pw, encoded string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$"+AlgArgon2id+"$"):
+		p, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return false, err
+		}
+		got := argon2.IDKey([]byte(pw), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return false, ErrMismatch
+		}
+		want := h.cfg.Argon2
+		return h.cfg.Algorithm != AlgArgon2id ||
+			p.Memory != want.Memory || p.Iterations != want.Iterations || p.Parallelism != want.Parallelism ||
+			uint32(len(salt)) != want.SaltLength || p.KeyLength != want.KeyLength, nil
+
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		if err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(pw)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, ErrMismatch
+			}
+			return false, err
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return false, err
+		}
+		return h.cfg.Algorithm != AlgBcrypt || cost != h.cfg.BcryptCost, nil
+
+	default:
+		return false, ErrUnknownFormat
+	}
+}
+
+// encodeArgon2 menulis hash dalam format PHC: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func encodeArgon2(p Argon2Params, salt, key []byte) string {
+	b64 := base64.RawStdEncoding
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgArgon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism, b64.EncodeToString(salt), b64.EncodeToString(key))
+}
+
+func decodeArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrUnknownFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrUnknownFormat
+	}
+	b64 := base64.RawStdEncoding
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnknownFormat
+	}
+	key, err := b64.DecodeString(parts[5])
+	if err != nil || len(key) == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, ErrUnknownFormat
+	}
+	p.SaltLength, p.KeyLength = uint32(len(salt)), uint32(len(key))
+	return p, salt, key, nil
+}
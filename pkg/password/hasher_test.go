@@ -0,0 +1,169 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// parameter kecil supaya test cepat; tetap lolos validasi NewHasher
+var testArgon2 = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestHasher(t *testing.T, cfg HasherConfig) Hasher {
+	t.Helper()
+	h, err := NewHasher(cfg)
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	return h
+}
+
+func TestNewHasherRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  HasherConfig
+	}{
+		{name: "algoritma tidak dikenal", cfg: HasherConfig{Algorithm: "md5"}},
+		{name: "bcrypt cost terlalu kecil", cfg: HasherConfig{BcryptCost: bcrypt.MinCost - 1}},
+		{name: "argon2 salt terlalu pendek", cfg: HasherConfig{Argon2: Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 32}}},
+		{name: "argon2 memory terlalu kecil", cfg: HasherConfig{Argon2: Argon2Params{Memory: 8, Iterations: 1, Parallelism: 2, SaltLength: 16, KeyLength: 32}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHasher(tt.cfg); err == nil {
+				t.Fatal("NewHasher error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestHasherRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    HasherConfig
+		prefix string
+	}{
+		{name: "argon2id", cfg: HasherConfig{Argon2: testArgon2}, prefix: "$argon2id$v=19$m=64,t=1,p=1$"},
+		{name: "bcrypt", cfg: HasherConfig{Algorithm: AlgBcrypt, BcryptCost: bcrypt.MinCost}, prefix: "$2a$04$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHasher(t, tt.cfg)
+			encoded, err := h.Hash("rahasia-123")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Fatalf("Hash = %q, want prefix %q", encoded, tt.prefix)
+			}
+			rehash, err := h.Verify("rahasia-123", encoded)
+			if err != nil || rehash {
+				t.Fatalf("Verify benar = (%v, %v), want (false, nil)", rehash, err)
+			}
+			if _, err := h.Verify("rahasia-124", encoded); !errors.Is(err, ErrMismatch) {
+				t.Fatalf("Verify salah = %v, want ErrMismatch", err)
+			}
+		})
+	}
+}
+
+func TestHasherNeedsRehash(t *testing.T) {
+	argon := newTestHasher(t, HasherConfig{Argon2: testArgon2})
+	stronger := testArgon2
+	stronger.Iterations = 2
+	argonStronger := newTestHasher(t, HasherConfig{Argon2: stronger})
+	bc := newTestHasher(t, HasherConfig{Algorithm: AlgBcrypt, BcryptCost: bcrypt.MinCost})
+	bcStronger := newTestHasher(t, HasherConfig{Algorithm: AlgBcrypt, BcryptCost: bcrypt.MinCost + 1})
+
+	tests := []struct {
+		name   string
+		from   Hasher
+		verify Hasher
+		want   bool
+	}{
+		{name: "argon2 parameter sama", from: argon, verify: argon, want: false},
+		{name: "argon2 parameter berubah", from: argon, verify: argonStronger, want: true},
+		{name: "argon2 ke bcrypt", from: argon, verify: bc, want: true},
+		{name: "bcrypt ke argon2", from: bc, verify: argon, want: true},
+		{name: "bcrypt cost berubah", from: bc, verify: bcStronger, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.from.Hash("rahasia-123")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			got, err := tt.verify.Verify("rahasia-123", encoded)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("needsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasherRejectsMalformedHash(t *testing.T) {
+	h := newTestHasher(t, HasherConfig{Argon2: testArgon2})
+	valid, err := h.Hash("rahasia-123")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	parts := strings.Split(valid, "$") // "", argon2id, v=19, m=..,t=..,p=.., salt, key
+	join := func(p ...string) string { return strings.Join(p, "$") }
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "kosong", encoded: ""},
+		{name: "plaintext", encoded: "rahasia-123"},
+		{name: "algoritma lain", encoded: "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5"},
+		{name: "bagian kurang", encoded: join(parts[:5]...)},
+		{name: "bagian lebih", encoded: valid + "$x"},
+		{name: "versi salah", encoded: join("", parts[1], "v=16", parts[3], parts[4], parts[5])},
+		{name: "versi bukan angka", encoded: join("", parts[1], "v=x", parts[3], parts[4], parts[5])},
+		{name: "parameter rusak", encoded: join("", parts[1], parts[2], "m=64", parts[4], parts[5])},
+		{name: "iterasi nol", encoded: join("", parts[1], parts[2], "m=64,t=0,p=1", parts[4], parts[5])},
+		{name: "salt bukan base64", encoded: join("", parts[1], parts[2], parts[3], "!!", parts[5])},
+		{name: "hash bukan base64", encoded: join("", parts[1], parts[2], parts[3], parts[4], "!!")},
+		{name: "hash kosong", encoded: join("", parts[1], parts[2], parts[3], parts[4], "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Verify("rahasia-123", tt.encoded); !errors.Is(err, ErrUnknownFormat) {
+				t.Fatalf("Verify(%q) = %v, want ErrUnknownFormat", tt.encoded, err)
+			}
+		})
+	}
+}
+
+func TestArgon2EncodeDecode(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encoded := encodeArgon2(testArgon2, salt, key)
+	if want := "$argon2id$v=19$m=64,t=1,p=1$MDEyMzQ1Njc4OWFiY2RlZg$MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY"; encoded != want {
+		t.Fatalf("encodeArgon2 = %q, want %q", encoded, want)
+	}
+	p, gotSalt, gotKey, err := decodeArgon2(encoded)
+	if err != nil {
+		t.Fatalf("decodeArgon2: %v", err)
+	}
+	if p != testArgon2 || string(gotSalt) != string(salt) || string(gotKey) != string(key) {
+		t.Fatalf("decodeArgon2 = (%+v, %q, %q), want (%+v, %q, %q)", p, gotSalt, gotKey, testArgon2, salt, key)
+	}
+}
+
+// bcrypt menolak (bukan memotong) password > BcryptMaxBytes, karena itu Policy.MaxBytes dipasang
+func TestBcryptRejectsLongPassword(t *testing.T) {
+	h := newTestHasher(t, HasherConfig{Algorithm: AlgBcrypt, BcryptCost: bcrypt.MinCost})
+	if _, err := h.Hash(strings.Repeat("a", BcryptMaxBytes)); err != nil {
+		t.Fatalf("Hash %d byte: %v", BcryptMaxBytes, err)
+	}
+	if _, err := h.Hash(strings.Repeat("a", BcryptMaxBytes+1)); !errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		t.Fatalf("Hash %d byte = %v, want bcrypt.ErrPasswordTooLong", BcryptMaxBytes+1, err)
+	}
+}
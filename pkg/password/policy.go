@@ -0,0 +1,160 @@
+// Package password memvalidasi password baru terhadap kebijakan (panjang, jenis karakter,
+// tidak memuat email/nama, skor kekuatan) dan daftar password yang pernah bocor, serta
+// membuat / memverifikasi hash password (argon2id atau bcrypt).
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Policy = aturan password baru. Nilai nol = aturan tsb tidak dipakai.
+type Policy struct {
+	MinLength     int
+	MaxLength     int // dalam karakter (rune)
+	MaxBytes      int // dalam byte UTF-8, mis. BcryptMaxBytes jika hash memakai bcrypt
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// DisallowUserInfo menolak password yang memuat nama / bagian lokal email user
+	DisallowUserInfo bool
+	// MinStrength = skor minimal 0..4 (lihat Strength)
+	MinStrength int
+	// Breached boleh nil; daftar password umum bawaan selalu dicek
+	Breached BreachedChecker
+}
+
+// UserInfo = data user yang tidak boleh muncul di password
+type UserInfo struct {
+	Email string
+	Name  string
+}
+
+// ViolationError berisi semua aturan yang dilanggar
+type ViolationError struct {
+	Reasons []string
+}
+
+func (e *ViolationError) Error() string { return strings.Join(e.Reasons, "; ") }
+
+// Validate mengembalikan *ViolationError jika password melanggar kebijakan. Error lain
+// (mis. gagal membaca daftar breached) dikembalikan apa adanya.
+func (p *Policy) Validate(pw string, info UserInfo) error {
+	var reasons []string
+	n := len([]rune(pw))
+	if p.MinLength > 0 && n < p.MinLength {
+		reasons = append(reasons, "password minimal "+strconv.Itoa(p.MinLength)+" karakter")
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		reasons = append(reasons, "password maksimal "+strconv.Itoa(p.MaxLength)+" karakter")
+	} else if p.MaxBytes > 0 && len(pw) > p.MaxBytes {
+		reasons = append(reasons, "password maksimal "+strconv.Itoa(p.MaxBytes)+" byte (huruf non-ASCII dihitung lebih dari 1 byte)")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		reasons = append(reasons, "password harus mengandung huruf besar")
+	}
+	if p.RequireLower && !lower {
+		reasons = append(reasons, "password harus mengandung huruf kecil")
+	}
+	if p.RequireDigit && !digit {
+		reasons = append(reasons, "password harus mengandung angka")
+	}
+	if p.RequireSymbol && !symbol {
+		reasons = append(reasons, "password harus mengandung simbol")
+	}
+
+	if p.DisallowUserInfo && containsUserInfo(pw, info) {
+		reasons = append(reasons, "password tidak boleh memuat nama atau email")
+	}
+
+	if isCommon(pw) {
+		reasons = append(reasons, "password terlalu umum")
+	} else if p.MinStrength > 0 && Strength(pw, info) < p.MinStrength {
+		reasons = append(reasons, "password terlalu lemah, gunakan kombinasi yang lebih panjang dan acak")
+	}
+
+	if p.Breached != nil && len(reasons) == 0 {
+		breached, err := p.Breached.Breached(pw)
+		if err != nil {
+			return err
+		}
+		if breached {
+			reasons = append(reasons, "password pernah bocor di kebocoran data, gunakan password lain")
+		}
+	}
+
+	if len(reasons) > 0 {
+		return &ViolationError{Reasons: reasons}
+	}
+	return nil
+}
+
+// token nama/email dengan panjang >= 3 dianggap memuat user info
+func containsUserInfo(pw string, info UserInfo) bool {
+	lower := strings.ToLower(pw)
+	for _, tok := range userTokens(info) {
+		if strings.Contains(lower, tok) {
+			return true
+		}
+	}
+	return false
+}
+
+func userTokens(info UserInfo) []string {
+	var out []string
+	local, _, _ := strings.Cut(strings.ToLower(info.Email), "@")
+	fields := strings.FieldsFunc(local+" "+strings.ToLower(info.Name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(local) >= 3 {
+		out = append(out, local)
+	}
+	for _, f := range fields {
+		if len([]rune(f)) >= 3 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+//go:embed common.txt
+var commonList string
+
+var common = func() map[string]bool {
+	m := map[string]bool{}
+	sc := bufio.NewScanner(strings.NewReader(commonList))
+	for sc.Scan() {
+		if w := strings.TrimSpace(sc.Text()); w != "" {
+			m[w] = true
+		}
+	}
+	return m
+}()
+
+// isCommon: cocok dengan daftar bawaan, juga setelah angka/simbol di belakang dibuang ("Password123!")
+func isCommon(pw string) bool {
+	lower := strings.ToLower(pw)
+	if common[lower] {
+		return true
+	}
+	base := strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	return len(base) >= 4 && common[base]
+}
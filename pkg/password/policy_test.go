@@ -0,0 +1,80 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type fakeBreached struct {
+	hit bool
+	err error
+}
+
+func (f fakeBreached) Breached(string) (bool, error) { return f.hit, f.err }
+
+func TestPolicyValidate(t *testing.T) {
+	strict := Policy{
+		MinLength:        10,
+		MaxLength:        64,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUserInfo: true,
+		MinStrength:      3,
+	}
+	info := UserInfo{Email: "budi.santoso@example.com", Name: "Budi Santoso"}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		pw     string
+		want   []string // alasan yang harus muncul; nil = lolos
+	}{
+		{name: "kuat lolos", policy: strict, pw: "Kx9#vTq2!mWz"},
+		{name: "terlalu pendek", policy: Policy{MinLength: 10}, pw: "Kx9#vT", want: []string{"password minimal 10 karakter"}},
+		{name: "terlalu panjang", policy: Policy{MaxLength: 4}, pw: "Kx9#vT", want: []string{"password maksimal 4 karakter"}},
+		{name: "panjang dihitung per rune", policy: Policy{MaxLength: 4}, pw: "ääää"},
+		{name: "batas byte bcrypt", policy: Policy{MaxLength: 72, MaxBytes: BcryptMaxBytes}, pw: strings.Repeat("ä", 40), want: []string{"password maksimal 72 byte (huruf non-ASCII dihitung lebih dari 1 byte)"}},
+		{name: "tepat batas byte", policy: Policy{MaxBytes: BcryptMaxBytes}, pw: strings.Repeat("ä", 36)},
+		{name: "tanpa huruf besar", policy: strict, pw: "kx9#vtq2!mwz", want: []string{"password harus mengandung huruf besar"}},
+		{name: "tanpa huruf kecil", policy: strict, pw: "KX9#VTQ2!MWZ", want: []string{"password harus mengandung huruf kecil"}},
+		{name: "tanpa angka", policy: strict, pw: "Kxa#vTqb!mWz", want: []string{"password harus mengandung angka"}},
+		{name: "tanpa simbol", policy: strict, pw: "Kx9avTq2bmWz", want: []string{"password harus mengandung simbol"}},
+		{name: "memuat nama", policy: strict, pw: "Santoso#2024xQ", want: []string{"password tidak boleh memuat nama atau email"}},
+		{name: "memuat email", policy: Policy{DisallowUserInfo: true}, pw: "xbudi.santosox", want: []string{"password tidak boleh memuat nama atau email"}},
+		{name: "password umum", policy: Policy{}, pw: "password", want: []string{"password terlalu umum"}},
+		{name: "password umum dengan akhiran", policy: Policy{}, pw: "Password123!", want: []string{"password terlalu umum"}},
+		{name: "terlalu lemah", policy: Policy{MinStrength: 3}, pw: "abcdefgh", want: []string{"password terlalu lemah, gunakan kombinasi yang lebih panjang dan acak"}},
+		{name: "breached", policy: Policy{Breached: fakeBreached{hit: true}}, pw: "Kx9#vTq2!mWz", want: []string{"password pernah bocor di kebocoran data, gunakan password lain"}},
+		{name: "breached tidak dicek jika sudah melanggar", policy: Policy{MinLength: 20, Breached: fakeBreached{hit: true}}, pw: "Kx9#vTq2!mWz", want: []string{"password minimal 20 karakter"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.pw, info)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v, want nil", tt.pw, err)
+				}
+				return
+			}
+			var ve *ViolationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Validate(%q) = %v, want *ViolationError", tt.pw, err)
+			}
+			if !slices.Equal(ve.Reasons, tt.want) {
+				t.Fatalf("Validate(%q) reasons = %q, want %q", tt.pw, ve.Reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyValidateBreachedError(t *testing.T) {
+	want := errors.New("baca gagal")
+	p := Policy{Breached: fakeBreached{err: want}}
+	if err := p.Validate("Kx9#vTq2!mWz", UserInfo{}); !errors.Is(err, want) {
+		t.Fatalf("Validate = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,150 @@
+package password
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strength memberi skor 0 (sangat lemah) s/d 4 (kuat) dengan gaya zxcvbn: estimasi entropi
+// (bit) di mana karakter yang mengikuti pola (pengulangan, urutan abc/123, baris keyboard)
+// dan kata umum / data user hampir tidak menambah entropi.
+func Strength(pw string, info UserInfo) int {
+	bits := entropyBits(pw, info)
+	switch {
+	case bits < 20:
+		return 0
+	case bits < 30:
+		return 1
+	case bits < 45:
+		return 2
+	case bits < 60:
+		return 3
+	default:
+		return 4
+	}
+}
+
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+var leet = strings.NewReplacer("0", "o", "1", "l", "3", "e", "4", "a", "@", "a", "$", "s", "5", "s", "7", "t")
+
+func entropyBits(pw string, info UserInfo) float64 {
+	runes := []rune(strings.ToLower(pw))
+	if len(runes) == 0 {
+		return 0
+	}
+	perChar := math.Log2(float64(charsetSize(pw)))
+
+	// karakter yang bagian dari kata umum / data user (setelah normalisasi leet) dihitung
+	// per kata, bukan per karakter
+	covered := make([]bool, len(runes))
+	var bits float64
+	norm := leet.Replace(string(runes)) // penggantian 1 rune → 1 rune, indeks tetap sejajar
+	for _, w := range append(userTokens(info), commonWords...) {
+		for off := 0; ; {
+			j := strings.Index(norm[off:], w)
+			if j < 0 {
+				break
+			}
+			start := utf8.RuneCountInString(norm[:off+j])
+			if markCovered(covered, start, start+utf8.RuneCountInString(w)) {
+				bits += wordBits
+			}
+			off += j + len(w)
+		}
+	}
+
+	for i := range runes {
+		switch {
+		case covered[i]:
+		case i > 0 && followsPattern(runes, i):
+			bits++
+		default:
+			bits += perChar
+		}
+	}
+	return bits
+}
+
+// markCovered menandai rune [start,end); true jika ada yang belum tertandai sebelumnya
+func markCovered(covered []bool, start, end int) bool {
+	fresh := false
+	for k := start; k < end; k++ {
+		if !covered[k] {
+			covered[k] = true
+			fresh = true
+		}
+	}
+	return fresh
+}
+
+// followsPattern: karakter ke-i mengulang, melanjutkan urutan, atau tetangga di baris keyboard
+func followsPattern(r []rune, i int) bool {
+	prev, cur := r[i-1], r[i]
+	if cur == prev {
+		return true
+	}
+	if d := cur - prev; d == 1 || d == -1 {
+		return true
+	}
+	for _, row := range keyboardRows {
+		a, b := strings.IndexRune(row, prev), strings.IndexRune(row, cur)
+		if a >= 0 && b >= 0 && (b-a == 1 || a-b == 1) {
+			return true
+		}
+	}
+	return false
+}
+
+func charsetSize(pw string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	n := 0
+	if lower {
+		n += 26
+	}
+	if upper {
+		n += 26
+	}
+	if digit {
+		n += 10
+	}
+	if symbol {
+		n += 33
+	}
+	return max(n, 2)
+}
+
+// kata dari daftar bawaan yang cukup panjang untuk dicari sebagai substring, terpanjang dulu
+var commonWords = func() []string {
+	var out []string
+	for w := range common {
+		if len(w) >= 4 && strings.IndexFunc(w, unicode.IsLetter) >= 0 {
+			out = append(out, w)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i]) != len(out[j]) {
+			return len(out[i]) > len(out[j])
+		}
+		return out[i] < out[j]
+	})
+	return out
+}()
+
+// entropi satu kata umum ≈ memilih satu kata dari daftar
+var wordBits = math.Log2(float64(len(common))) + 1
@@ -0,0 +1,28 @@
+package password
+
+import "testing"
+
+func TestStrength(t *testing.T) {
+	info := UserInfo{Email: "budi@example.com", Name: "Budi Santoso"}
+	tests := []struct {
+		name     string
+		pw       string
+		min, max int
+	}{
+		{name: "kosong", pw: "", min: 0, max: 0},
+		{name: "pengulangan", pw: "aaaaaaaaaaaa", min: 0, max: 0},
+		{name: "urutan", pw: "abcdefgh12345678", min: 0, max: 1},
+		{name: "baris keyboard", pw: "qwertyuiop", min: 0, max: 1},
+		{name: "kata umum leet", pw: "P@ssw0rd", min: 0, max: 1},
+		{name: "data user", pw: "budisantoso", min: 0, max: 1},
+		{name: "acak pendek", pw: "Kx9#vT", min: 1, max: 2},
+		{name: "acak panjang", pw: "Kx9#vTq2!mWz", min: 4, max: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strength(tt.pw, info); got < tt.min || got > tt.max {
+				t.Fatalf("Strength(%q) = %d, want %d..%d", tt.pw, got, tt.min, tt.max)
+			}
+		})
+	}
+}
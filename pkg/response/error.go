@@ -1,7 +1,9 @@
 package response
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/ariyaagustian/gin-boilerplate/pkg/apperr"
 	"github.com/gin-gonic/gin"
@@ -14,6 +16,9 @@ func WriteError(c *gin.Context, err error) {
 		if ae.HTTPStatus >= 500 {
 			c.Error(ae)
 		}
+		if ae.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(ae.RetryAfter.Seconds()))))
+		}
 		c.JSON(ae.HTTPStatus, gin.H{
 			"error": gin.H{
 				"code":    ae.Code,